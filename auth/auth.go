@@ -1,18 +1,25 @@
 package auth
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
 )
 
 // AuthType represents the type of authentication
 type AuthType string
 
 const (
-	AuthTypeBasic AuthType = "basic"
-	AuthTypeJWT   AuthType = "jwt"
+	AuthTypeBasic               AuthType = "basic"
+	AuthTypeJWT                 AuthType = "jwt"
+	AuthTypeWooCommerceKeys     AuthType = "woocommerce_keys"
+	AuthTypeApplicationPassword AuthType = "application_password"
+	AuthTypeCookie              AuthType = "cookie"
 )
 
 // Authenticator interface defines methods for authentication
@@ -23,6 +30,68 @@ type Authenticator interface {
 	Type() AuthType
 }
 
+// HTTPAuthenticator is implemented by authenticators that make requests of
+// their own, e.g. to log in. The client passes them its HTTP client so that
+// these requests share its transport and middleware.
+type HTTPAuthenticator interface {
+	Authenticator
+	SetHTTPClient(client utils.HTTPClient)
+}
+
+// TransportAuthenticator is implemented by authenticators that make
+// requests with an HTTP client of their own, e.g. to keep the cookies of a
+// login. The client passes them a transport that sends requests through its
+// middleware, so that these requests carry its User-Agent and are observed
+// like the others.
+type TransportAuthenticator interface {
+	Authenticator
+	SetTransport(transport http.RoundTripper)
+}
+
+// Validator is implemented by authenticators that can check their
+// credentials against the server
+type Validator interface {
+	Validate(ctx context.Context) error
+}
+
+// ResponseObserver is implemented by authenticators that inspect the
+// responses to authenticated requests, e.g. to discard credentials the
+// server rejected
+type ResponseObserver interface {
+	ObserveResponse(resp *http.Response)
+}
+
+// RefreshReporter is implemented by authenticators that know whether
+// refreshing them can currently yield new credentials
+type RefreshReporter interface {
+	CanRefresh() bool
+}
+
+// ContextRefresher is implemented by authenticators whose refresh can be
+// cancelled, e.g. because it logs in
+type ContextRefresher interface {
+	RefreshContext(ctx context.Context) error
+}
+
+// Refreshable reports whether refreshing a can yield new credentials.
+// Authenticators that implement RefreshReporter are asked; others are
+// assumed to be refreshable.
+func Refreshable(a Authenticator) bool {
+	if reporter, ok := a.(RefreshReporter); ok {
+		return reporter.CanRefresh()
+	}
+	return true
+}
+
+// RefreshWithContext refreshes a, giving up when ctx is done if a
+// implements ContextRefresher
+func RefreshWithContext(ctx context.Context, a Authenticator) error {
+	if refresher, ok := a.(ContextRefresher); ok {
+		return refresher.RefreshContext(ctx)
+	}
+	return a.Refresh()
+}
+
 // BasicAuth implements HTTP Basic Authentication
 type BasicAuth struct {
 	username string
@@ -59,93 +128,235 @@ func (b *BasicAuth) Refresh() error {
 	return nil
 }
 
+// CanRefresh implements RefreshReporter. Basic auth credentials are static.
+func (b *BasicAuth) CanRefresh() bool {
+	return false
+}
+
 // Type returns the authentication type
 func (b *BasicAuth) Type() AuthType {
 	return AuthTypeBasic
 }
 
-// JWTAuth implements JWT Authentication
+// jwtExpiryBuffer is how long before its expiration a JWT token stops being
+// used
+const jwtExpiryBuffer = 5 * time.Minute
+
+// DefaultJWTRefreshAhead is how long before the expiry buffer a JWT token is
+// refreshed in the background
+const DefaultJWTRefreshAhead = 5 * time.Minute
+
+// jwtRetryAfterFailure is how long to wait before trying a background
+// refresh again after one failed
+const jwtRetryAfterFailure = 30 * time.Second
+
+// JWTAuth implements JWT Authentication. It is safe for concurrent use:
+// simultaneous refreshes are coalesced into a single call to the refresh
+// function, and tokens about to expire are refreshed in the background
+// while the current one is still in use.
 type JWTAuth struct {
-	token     string
-	expiresAt time.Time
+	mu           sync.RWMutex
+	token        string
+	expiresAt    time.Time
 	refreshToken string
-	refreshFunc func(refreshToken string) (string, time.Time, error)
+	refreshFunc  func(refreshToken string) (string, time.Time, error)
+	// obtain, if set, replaces refreshFunc to get tokens without a refresh
+	// token, e.g. by logging in
+	obtain       func(ctx context.Context) (string, time.Time, error)
+	refreshAhead time.Duration
+	onRefresh    func(token string, expiresAt time.Time)
+	
+	// refreshing is the refresh in progress, if any
+	refreshing *jwtRefresh
+	// lastFailure is when the last background refresh failed
+	lastFailure time.Time
+}
+
+// jwtRefresh is a refresh in progress that concurrent callers wait for
+type jwtRefresh struct {
+	done chan struct{}
+	err  error
+	// abandoned is set when the caller that started the refresh gave up,
+	// so that the callers waiting for it try again
+	abandoned bool
 }
 
 // NewJWTAuth creates a new JWTAuth authenticator
 func NewJWTAuth(token string, expiresAt time.Time) *JWTAuth {
 	return &JWTAuth{
-		token:     token,
-		expiresAt: expiresAt,
+		token:        token,
+		expiresAt:    expiresAt,
+		refreshAhead: DefaultJWTRefreshAhead,
 	}
 }
 
 // NewJWTAuthWithRefresh creates a new JWTAuth authenticator with refresh capability
 func NewJWTAuthWithRefresh(token string, expiresAt time.Time, refreshToken string, refreshFunc func(string) (string, time.Time, error)) *JWTAuth {
 	return &JWTAuth{
-		token:       token,
-		expiresAt:   expiresAt,
+		token:        token,
+		expiresAt:    expiresAt,
 		refreshToken: refreshToken,
-		refreshFunc: refreshFunc,
+		refreshFunc:  refreshFunc,
+		refreshAhead: DefaultJWTRefreshAhead,
 	}
 }
 
 // Authenticate adds JWT Bearer token to the request
 func (j *JWTAuth) Authenticate(req *http.Request) error {
-	if j.token == "" {
+	j.mu.RLock()
+	token, expiresAt, canRefresh, refreshAhead := j.token, j.expiresAt, j.canRefresh(), j.refreshAhead
+	j.mu.RUnlock()
+	
+	if token == "" && !canRefresh {
 		return fmt.Errorf("JWT token is required")
 	}
 	
 	// Check if token is expired and try to refresh
-	if !j.IsValid() && j.refreshFunc != nil {
-		if err := j.Refresh(); err != nil {
+	if !jwtValid(token, expiresAt, 0) && canRefresh {
+		if err := j.RefreshContext(req.Context()); err != nil {
 			return fmt.Errorf("failed to refresh token: %w", err)
 		}
+		
+		j.mu.RLock()
+		token, expiresAt = j.token, j.expiresAt
+		j.mu.RUnlock()
 	}
 	
-	if !j.IsValid() {
+	if !jwtValid(token, expiresAt, 0) {
 		return fmt.Errorf("JWT token is expired and cannot be refreshed")
 	}
 	
-	req.Header.Set("Authorization", "Bearer "+j.token)
+	// Refresh ahead of time so that requests never wait for it
+	if canRefresh && !jwtValid(token, expiresAt, refreshAhead) {
+		j.refreshInBackground()
+	}
+	
+	req.Header.Set("Authorization", "Bearer "+token)
 	return nil
 }
 
 // IsValid checks if the JWT token is still valid
 func (j *JWTAuth) IsValid() bool {
-	if j.token == "" {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return jwtValid(j.token, j.expiresAt, 0)
+}
+
+// jwtValid reports whether token can still be used for longer than margin
+// plus the expiry buffer
+func jwtValid(token string, expiresAt time.Time, margin time.Duration) bool {
+	if token == "" {
 		return false
 	}
 	
 	// If no expiration time is set, assume it's valid
-	if j.expiresAt.IsZero() {
+	if expiresAt.IsZero() {
 		return true
 	}
 	
 	// Add a 5-minute buffer before expiration
-	return time.Now().Add(5 * time.Minute).Before(j.expiresAt)
+	return time.Now().Add(jwtExpiryBuffer + margin).Before(expiresAt)
 }
 
-// Refresh refreshes the JWT token using the refresh token
+// CanRefresh implements RefreshReporter. The token can be refreshed when
+// there is a refresh function and a refresh token.
+func (j *JWTAuth) CanRefresh() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.canRefresh()
+}
+
+// canRefresh reports whether the token can be refreshed. j.mu must be held.
+func (j *JWTAuth) canRefresh() bool {
+	return j.obtain != nil || (j.refreshFunc != nil && j.refreshToken != "")
+}
+
+// Refresh refreshes the JWT token using the refresh token. Concurrent calls
+// share a single call to the refresh function and its result.
 func (j *JWTAuth) Refresh() error {
-	if j.refreshFunc == nil {
-		return fmt.Errorf("no refresh function provided")
+	return j.RefreshContext(context.Background())
+}
+
+// RefreshContext is like Refresh, but gives up when ctx is done. A login
+// started by JWTLoginAuth is aborted along with it.
+func (j *JWTAuth) RefreshContext(ctx context.Context) error {
+	j.mu.Lock()
+	for j.refreshing != nil {
+		call := j.refreshing
+		j.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if !call.abandoned {
+			return call.err
+		}
+		j.mu.Lock()
 	}
 	
-	if j.refreshToken == "" {
-		return fmt.Errorf("no refresh token available")
+	obtain := j.obtain
+	if obtain == nil {
+		if j.refreshFunc == nil {
+			j.mu.Unlock()
+			return fmt.Errorf("no refresh function provided")
+		}
+		
+		if j.refreshToken == "" {
+			j.mu.Unlock()
+			return fmt.Errorf("no refresh token available")
+		}
+		
+		refreshFunc, refreshToken := j.refreshFunc, j.refreshToken
+		obtain = func(context.Context) (string, time.Time, error) {
+			return refreshFunc(refreshToken)
+		}
+	}
+	
+	call := &jwtRefresh{done: make(chan struct{})}
+	j.refreshing = call
+	j.mu.Unlock()
+	defer close(call.done)
+	
+	newToken, expiresAt, err := obtain(ctx)
+	
+	j.mu.Lock()
+	j.refreshing = nil
+	if err != nil && ctx.Err() != nil {
+		call.abandoned = true
+	} else if err != nil {
+		j.lastFailure = time.Now()
+	} else {
+		j.token = newToken
+		j.expiresAt = expiresAt
 	}
+	onRefresh := j.onRefresh
+	j.mu.Unlock()
 	
-	newToken, expiresAt, err := j.refreshFunc(j.refreshToken)
+	call.err = err
 	if err != nil {
 		return err
 	}
 	
-	j.token = newToken
-	j.expiresAt = expiresAt
+	if onRefresh != nil {
+		onRefresh(newToken, expiresAt)
+	}
 	return nil
 }
 
+// refreshInBackground starts a refresh unless one is in progress or the
+// last one failed recently
+func (j *JWTAuth) refreshInBackground() {
+	j.mu.RLock()
+	skip := j.refreshing != nil || time.Since(j.lastFailure) < jwtRetryAfterFailure
+	j.mu.RUnlock()
+	if skip {
+		return
+	}
+	
+	go j.Refresh()
+}
+
 // Type returns the authentication type
 func (j *JWTAuth) Type() AuthType {
 	return AuthTypeJWT
@@ -153,27 +364,88 @@ func (j *JWTAuth) Type() AuthType {
 
 // SetToken updates the JWT token and expiration time
 func (j *JWTAuth) SetToken(token string, expiresAt time.Time) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
 	j.token = token
 	j.expiresAt = expiresAt
 }
 
 // GetToken returns the current JWT token
 func (j *JWTAuth) GetToken() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
 	return j.token
 }
 
+// GetExpiresAt returns the expiration time of the current JWT token, or the
+// zero time if it does not expire
+func (j *JWTAuth) GetExpiresAt() time.Time {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.expiresAt
+}
+
+// invalidate discards token if it is still the current one, so that the
+// next request gets a new one
+func (j *JWTAuth) invalidate(token string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.token == token {
+		j.token = ""
+		j.expiresAt = time.Time{}
+	}
+}
+
+// getRefreshToken returns the refresh token
+func (j *JWTAuth) getRefreshToken() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.refreshToken
+}
+
 // SetRefreshToken sets the refresh token
 func (j *JWTAuth) SetRefreshToken(refreshToken string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
 	j.refreshToken = refreshToken
 }
 
+// SetRefreshAhead sets how long before the 5-minute expiry buffer the token
+// is refreshed in the background. Zero disables background refreshes.
+func (j *JWTAuth) SetRefreshAhead(d time.Duration) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.refreshAhead = d
+}
+
+// SetOnRefresh sets a function that is called with every new token after a
+// successful refresh, for instance to persist it. It is called from the
+// goroutine doing the refresh, which may be a background one.
+func (j *JWTAuth) SetOnRefresh(fn func(token string, expiresAt time.Time)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.onRefresh = fn
+}
+
 // Config represents authentication configuration
 type Config struct {
-	Type         AuthType `json:"type"`
-	Username     string   `json:"username,omitempty"`
-	Password     string   `json:"password,omitempty"`
-	Token        string   `json:"token,omitempty"`
-	RefreshToken string   `json:"refresh_token,omitempty"`
+	Type AuthType `json:"type" yaml:"type"`
+	// BaseURL is the site that issues tokens. The client fills it in with
+	// its own base URL when empty.
+	BaseURL      string `json:"base_url,omitempty" yaml:"base_url,omitempty"`
+	Username     string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password     string `json:"password,omitempty" yaml:"password,omitempty"`
+	Token        string `json:"token,omitempty" yaml:"token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty" yaml:"refresh_token,omitempty"`
+	// ConsumerKey and ConsumerSecret are the WooCommerce REST API keys
+	ConsumerKey    string `json:"consumer_key,omitempty" yaml:"consumer_key,omitempty"`
+	ConsumerSecret string `json:"consumer_secret,omitempty" yaml:"consumer_secret,omitempty"`
+	// OAuthSignatureMethod signs WooCommerce requests over plain HTTP.
+	// Defaults to HMAC-SHA256.
+	OAuthSignatureMethod OAuthSignatureMethod `json:"oauth_signature_method,omitempty" yaml:"oauth_signature_method,omitempty"`
+	// KeysInQueryString sends WooCommerce keys over HTTPS in the query
+	// string instead of with Basic Authentication
+	KeysInQueryString bool `json:"keys_in_query_string,omitempty" yaml:"keys_in_query_string,omitempty"`
 }
 
 // NewAuthenticator creates a new authenticator based on the config
@@ -185,10 +457,46 @@ func NewAuthenticator(config Config) (Authenticator, error) {
 		}
 		return NewBasicAuth(config.Username, config.Password), nil
 	case AuthTypeJWT:
-		if config.Token == "" {
-			return nil, fmt.Errorf("token is required for JWT auth")
+		canLogin := config.Username != "" && config.Password != ""
+		if config.Token == "" && !canLogin {
+			return nil, fmt.Errorf("token or username and password are required for JWT auth")
+		}
+		
+		expiresAt, _ := JWTExpiry(config.Token)
+		if !canLogin && config.RefreshToken == "" {
+			return NewJWTAuth(config.Token, expiresAt), nil
+		}
+		
+		if config.BaseURL == "" {
+			return nil, fmt.Errorf("base URL is required to obtain JWT tokens")
+		}
+		jwt := NewJWTLoginAuth(config.BaseURL, config.Username, config.Password)
+		jwt.SetToken(config.Token, expiresAt)
+		jwt.SetRefreshToken(config.RefreshToken)
+		return jwt, nil
+	case AuthTypeApplicationPassword:
+		if config.Username == "" || config.Password == "" {
+			return nil, fmt.Errorf("username and application password are required")
+		}
+		return NewApplicationPasswordAuth(config.BaseURL, config.Username, config.Password), nil
+	case AuthTypeCookie:
+		if config.Username == "" || config.Password == "" {
+			return nil, fmt.Errorf("username and password are required for cookie auth")
+		}
+		if config.BaseURL == "" {
+			return nil, fmt.Errorf("base URL is required for cookie auth")
+		}
+		return NewCookieAuth(config.BaseURL, config.Username, config.Password), nil
+	case AuthTypeWooCommerceKeys:
+		if config.ConsumerKey == "" || config.ConsumerSecret == "" {
+			return nil, fmt.Errorf("consumer key and secret are required for WooCommerce auth")
+		}
+		keys := NewWooCommerceKeysAuth(config.ConsumerKey, config.ConsumerSecret)
+		if config.OAuthSignatureMethod != "" {
+			keys.SetSignatureMethod(config.OAuthSignatureMethod)
 		}
-		return NewJWTAuth(config.Token, time.Time{}), nil
+		keys.SetQueryString(config.KeysInQueryString)
+		return keys, nil
 	default:
 		return nil, fmt.Errorf("unsupported auth type: %s", config.Type)
 	}
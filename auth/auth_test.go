@@ -2,6 +2,8 @@ package auth
 
 import (
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -209,3 +211,70 @@ func TestNewAuthenticator_MissingCredentials(t *testing.T) {
 	}
 }
 
+
+func TestJWTAuth_Refresh_Concurrent(t *testing.T) {
+	var calls int32
+	refresh := func(refreshToken string) (string, time.Time, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		return "new-token", time.Now().Add(time.Hour), nil
+	}
+	auth := NewJWTAuthWithRefresh("old-token", time.Now().Add(-time.Minute), "refresh-token", refresh)
+	
+	var wg sync.WaitGroup
+	headers := make([]string, 20)
+	for i := range headers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", "https://example.com", nil)
+			if err := auth.Authenticate(req); err != nil {
+				t.Errorf("Authenticate() returned error: %v", err)
+			}
+			headers[i] = req.Header.Get("Authorization")
+		}(i)
+	}
+	wg.Wait()
+	
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected concurrent refreshes to be coalesced into 1, got %d", n)
+	}
+	for _, header := range headers {
+		if header != "Bearer new-token" {
+			t.Errorf("Expected the refreshed token, got '%s'", header)
+		}
+	}
+}
+
+func TestJWTAuth_RefreshInBackground(t *testing.T) {
+	refresh := func(refreshToken string) (string, time.Time, error) {
+		return "new-token", time.Now().Add(time.Hour), nil
+	}
+	// Still valid, but within the refresh-ahead window
+	auth := NewJWTAuthWithRefresh("old-token", time.Now().Add(8*time.Minute), "refresh-token", refresh)
+	
+	refreshed := make(chan string, 1)
+	auth.SetOnRefresh(func(token string, expiresAt time.Time) {
+		refreshed <- token
+	})
+	
+	req, _ := http.NewRequest("GET", "https://example.com", nil)
+	if err := auth.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() returned error: %v", err)
+	}
+	if header := req.Header.Get("Authorization"); header != "Bearer old-token" {
+		t.Errorf("Expected the current token to be used while refreshing, got '%s'", header)
+	}
+	
+	select {
+	case token := <-refreshed:
+		if token != "new-token" {
+			t.Errorf("Expected the hook to receive 'new-token', got '%s'", token)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the token to be refreshed in the background")
+	}
+	if token := auth.GetToken(); token != "new-token" {
+		t.Errorf("Expected token 'new-token', got '%s'", token)
+	}
+}
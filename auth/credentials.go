@@ -0,0 +1,500 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNoCredentials is returned by credential providers that found no
+// credentials, so that a ChainProvider moves on to the next one
+var ErrNoCredentials = stderrors.New("no credentials found")
+
+// CredentialProvider supplies the configuration of an authenticator, such
+// as the credentials from the environment or a secret store
+type CredentialProvider interface {
+	// Credentials returns the current credentials, or an error wrapping
+	// ErrNoCredentials if the provider has none
+	Credentials(ctx context.Context) (Config, error)
+}
+
+// credentialFields are the keys under which credentials are stored in
+// environment variables, files and secret directories, matching the JSON
+// names of the Config fields
+var credentialFields = []struct {
+	key string
+	set func(config *Config, value string) error
+}{
+	{"base_url", func(c *Config, v string) error { c.BaseURL = v; return nil }},
+	{"type", func(c *Config, v string) error { c.Type = AuthType(v); return nil }},
+	{"username", func(c *Config, v string) error { c.Username = v; return nil }},
+	{"password", func(c *Config, v string) error { c.Password = v; return nil }},
+	{"token", func(c *Config, v string) error { c.Token = v; return nil }},
+	{"refresh_token", func(c *Config, v string) error { c.RefreshToken = v; return nil }},
+	{"consumer_key", func(c *Config, v string) error { c.ConsumerKey = v; return nil }},
+	{"consumer_secret", func(c *Config, v string) error { c.ConsumerSecret = v; return nil }},
+	{"oauth_signature_method", func(c *Config, v string) error {
+		c.OAuthSignatureMethod = OAuthSignatureMethod(v)
+		return nil
+	}},
+	{"keys_in_query_string", func(c *Config, v string) (err error) {
+		c.KeysInQueryString, err = strconv.ParseBool(v)
+		return err
+	}},
+}
+
+// configFromValues builds a Config from the values returned by lookup for
+// each credential key. found is false if no value was set.
+func configFromValues(lookup func(key string) (string, bool)) (config Config, found bool, err error) {
+	for _, field := range credentialFields {
+		value, ok := lookup(field.key)
+		if !ok || value == "" {
+			continue
+		}
+		if err := field.set(&config, value); err != nil {
+			return Config{}, false, fmt.Errorf("invalid %s: %w", field.key, err)
+		}
+		found = true
+	}
+	return inferType(config), found, nil
+}
+
+// inferType sets the authentication type from the credentials present when
+// it is not set
+func inferType(config Config) Config {
+	if config.Type != "" {
+		return config
+	}
+	switch {
+	case config.ConsumerKey != "":
+		config.Type = AuthTypeWooCommerceKeys
+	case config.Token != "" || config.RefreshToken != "":
+		config.Type = AuthTypeJWT
+	case config.Username != "" && config.Password != "":
+		config.Type = AuthTypeBasic
+	}
+	return config
+}
+
+// EnvProvider reads credentials from environment variables named after the
+// credential keys with a prefix: DOKAN_BASE_URL, DOKAN_AUTH_TYPE,
+// DOKAN_USERNAME, DOKAN_PASSWORD, DOKAN_TOKEN, DOKAN_REFRESH_TOKEN,
+// DOKAN_CONSUMER_KEY, DOKAN_CONSUMER_SECRET, DOKAN_OAUTH_SIGNATURE_METHOD
+// and DOKAN_KEYS_IN_QUERY_STRING. When DOKAN_AUTH_TYPE is not set, the
+// type is inferred from the credentials present.
+type EnvProvider struct {
+	prefix string
+}
+
+// NewEnvProvider creates an EnvProvider for variables prefixed with DOKAN_
+func NewEnvProvider() *EnvProvider {
+	return &EnvProvider{prefix: "DOKAN_"}
+}
+
+// SetPrefix sets the prefix of the variables, e.g. "STAGING_DOKAN_"
+func (e *EnvProvider) SetPrefix(prefix string) {
+	e.prefix = prefix
+}
+
+// Credentials implements CredentialProvider
+func (e *EnvProvider) Credentials(ctx context.Context) (Config, error) {
+	config, found, err := configFromValues(func(key string) (string, bool) {
+		if key == "type" {
+			key = "auth_type"
+		}
+		return os.LookupEnv(e.prefix + strings.ToUpper(key))
+	})
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid credentials in environment: %w", err)
+	}
+	if !found {
+		return Config{}, fmt.Errorf("%w in %s* environment variables", ErrNoCredentials, e.prefix)
+	}
+	return config, nil
+}
+
+// FileProvider reads credentials from a YAML or JSON file holding named
+// profiles, with the keys of the JSON encoding of Config:
+//
+//	default:
+//	  base_url: https://example.com
+//	  username: admin
+//	  password: secret
+//	vendor:
+//	  base_url: https://example.com
+//	  type: application_password
+//	  username: vendor
+//	  password: abcd efgh ijkl mnop qrst uvwx
+//
+// Files whose name ends in .json, or whose content starts with "{", are read
+// as JSON. Unknown keys are rejected in both formats, so that a misspelled
+// one is not silently ignored.
+type FileProvider struct {
+	path    string
+	profile string
+}
+
+// DefaultCredentialsFile returns the path in DOKAN_CREDENTIALS_FILE, or
+// ~/.dokan/credentials
+func DefaultCredentialsFile() string {
+	if path := os.Getenv("DOKAN_CREDENTIALS_FILE"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".dokan", "credentials")
+}
+
+// NewFileProvider creates a FileProvider for a profile of the file at path.
+// An empty path uses DefaultCredentialsFile, and an empty profile uses
+// DOKAN_PROFILE, or "default".
+func NewFileProvider(path, profile string) *FileProvider {
+	if path == "" {
+		path = DefaultCredentialsFile()
+	}
+	if profile == "" {
+		profile = os.Getenv("DOKAN_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+	return &FileProvider{path: path, profile: profile}
+}
+
+// Credentials implements CredentialProvider
+func (f *FileProvider) Credentials(ctx context.Context) (Config, error) {
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return Config{}, fmt.Errorf("%w in %s", ErrNoCredentials, f.path)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	profiles, err := parseProfiles(f.path, data)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to parse credentials file %s: %w", f.path, err)
+	}
+
+	config, ok := profiles[f.profile]
+	if !ok {
+		return Config{}, fmt.Errorf("%w for profile %q in %s", ErrNoCredentials, f.profile, f.path)
+	}
+	return inferType(config), nil
+}
+
+// parseProfiles decodes the profiles of a credentials file, rejecting
+// unknown keys
+func parseProfiles(path string, data []byte) (map[string]Config, error) {
+	var profiles map[string]Config
+	if strings.HasSuffix(path, ".json") || bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&profiles); err != nil {
+			return nil, err
+		}
+		return profiles, nil
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&profiles); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// SecretDirProvider reads credentials from a directory holding one file
+// per credential key, such as the secrets mounted by Docker in /run/secrets
+// or a Kubernetes secret volume: base_url, username, password, token and
+// so on. Trailing newlines are ignored. Files are read on every call, so
+// that rotated secrets are picked up.
+type SecretDirProvider struct {
+	dir    string
+	prefix string
+}
+
+// NewSecretDirProvider creates a SecretDirProvider for the files in dir
+func NewSecretDirProvider(dir string) *SecretDirProvider {
+	return &SecretDirProvider{dir: dir}
+}
+
+// SetPrefix sets a prefix of the file names, e.g. "dokan_" to read
+// /run/secrets/dokan_password
+func (s *SecretDirProvider) SetPrefix(prefix string) {
+	s.prefix = prefix
+}
+
+// Credentials implements CredentialProvider
+func (s *SecretDirProvider) Credentials(ctx context.Context) (Config, error) {
+	var readErr error
+	config, found, err := configFromValues(func(key string) (string, bool) {
+		data, err := os.ReadFile(filepath.Join(s.dir, s.prefix+key))
+		if err != nil {
+			if !os.IsNotExist(err) && readErr == nil {
+				readErr = err
+			}
+			return "", false
+		}
+		return strings.TrimRight(string(data), "\r\n"), true
+	})
+	if readErr != nil {
+		return Config{}, fmt.Errorf("failed to read secret: %w", readErr)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid secret in %s: %w", s.dir, err)
+	}
+	if !found {
+		return Config{}, fmt.Errorf("%w in %s", ErrNoCredentials, s.dir)
+	}
+	return config, nil
+}
+
+// ChainProvider returns the credentials of the first of its providers that
+// has some
+type ChainProvider struct {
+	providers []CredentialProvider
+}
+
+// NewChainProvider creates a ChainProvider that tries providers in order
+func NewChainProvider(providers ...CredentialProvider) *ChainProvider {
+	return &ChainProvider{providers: providers}
+}
+
+// Credentials implements CredentialProvider
+func (c *ChainProvider) Credentials(ctx context.Context) (Config, error) {
+	for _, provider := range c.providers {
+		config, err := provider.Credentials(ctx)
+		if err == nil {
+			return config, nil
+		}
+		if !stderrors.Is(err, ErrNoCredentials) {
+			return Config{}, err
+		}
+	}
+	return Config{}, ErrNoCredentials
+}
+
+// DefaultCredentialsReloadInterval is how often a ProviderAuth checks its
+// provider for new credentials
+const DefaultCredentialsReloadInterval = 30 * time.Second
+
+// ProviderAuth authenticates requests with the credentials of a
+// CredentialProvider. It checks the provider for new credentials
+// periodically and when refreshed, e.g. after a 401 response, and switches
+// to a new authenticator when they change, so that rotated secrets are
+// picked up without restarting. A change of base URL is not applied.
+type ProviderAuth struct {
+	provider       CredentialProvider
+	defaultBaseURL string
+	interval       time.Duration
+
+	mu         sync.Mutex
+	config     Config
+	current    Authenticator
+	checked    time.Time
+	onError    func(err error)
+	httpClient utils.HTTPClient
+	transport  http.RoundTripper
+}
+
+// NewProviderAuth creates a ProviderAuth with the current credentials of
+// provider. defaultBaseURL is used by authenticators that need one when
+// the provider supplies none.
+func NewProviderAuth(ctx context.Context, provider CredentialProvider, defaultBaseURL string) (*ProviderAuth, error) {
+	p := &ProviderAuth{
+		provider:       provider,
+		defaultBaseURL: defaultBaseURL,
+		interval:       DefaultCredentialsReloadInterval,
+	}
+	if _, err := p.reload(ctx, true); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// SetReloadInterval sets how often the provider is checked for new
+// credentials. Zero disables the periodic checks, so that new credentials
+// are only read by Refresh.
+func (p *ProviderAuth) SetReloadInterval(interval time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.interval = interval
+}
+
+// SetOnReloadError sets a function called with the error when checking the
+// provider for new credentials fails while authenticating a request. The
+// previous credentials keep being used, so without it the failure would go
+// unnoticed.
+func (p *ProviderAuth) SetOnReloadError(fn func(err error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onError = fn
+}
+
+// Config returns the current credentials
+func (p *ProviderAuth) Config() Config {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.config
+}
+
+// Authenticator returns the authenticator for the current credentials
+func (p *ProviderAuth) Authenticator() Authenticator {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current
+}
+
+// reload gets the credentials from the provider, if forced or due, and
+// switches authenticators if they changed. It reports whether they did.
+// The provider is called without holding p.mu, so that requests are not
+// held up by it.
+func (p *ProviderAuth) reload(ctx context.Context, force bool) (bool, error) {
+	p.mu.Lock()
+	if !force && (p.interval <= 0 || time.Since(p.checked) < p.interval) {
+		p.mu.Unlock()
+		return false, nil
+	}
+	p.checked = time.Now()
+	p.mu.Unlock()
+
+	config, err := p.provider.Credentials(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.current != nil && config == p.config {
+		return false, nil
+	}
+
+	authConfig := config
+	if authConfig.BaseURL == "" {
+		authConfig.BaseURL = p.defaultBaseURL
+	}
+	authenticator, err := NewAuthenticator(authConfig)
+	if err != nil {
+		return false, err
+	}
+	if a, ok := authenticator.(HTTPAuthenticator); ok && p.httpClient != nil {
+		a.SetHTTPClient(p.httpClient)
+	}
+	if a, ok := authenticator.(TransportAuthenticator); ok && p.transport != nil {
+		a.SetTransport(p.transport)
+	}
+
+	p.config = config
+	p.current = authenticator
+	return true, nil
+}
+
+// Authenticate authenticates the request with the current credentials.
+// If the provider fails while checking for new ones, the previous
+// credentials keep being used and the error is passed to the function set
+// with SetOnReloadError.
+func (p *ProviderAuth) Authenticate(req *http.Request) error {
+	if _, err := p.reload(req.Context(), false); err != nil {
+		p.mu.Lock()
+		onError := p.onError
+		p.mu.Unlock()
+		if onError != nil {
+			onError(err)
+		}
+	}
+	return p.Authenticator().Authenticate(req)
+}
+
+// IsValid checks if the current credentials are valid
+func (p *ProviderAuth) IsValid() bool {
+	return p.Authenticator().IsValid()
+}
+
+// Refresh gets the credentials from the provider and, if they did not
+// change, refreshes the current authenticator. It returns an error when
+// neither yields new credentials, so that a rejected request is not
+// replayed with the same ones.
+func (p *ProviderAuth) Refresh() error {
+	return p.RefreshContext(context.Background())
+}
+
+// RefreshContext implements ContextRefresher
+func (p *ProviderAuth) RefreshContext(ctx context.Context) error {
+	changed, err := p.reload(ctx, true)
+	if err != nil {
+		return err
+	}
+	if changed {
+		return nil
+	}
+
+	current := p.Authenticator()
+	if !Refreshable(current) {
+		return fmt.Errorf("credentials from provider have not changed")
+	}
+	return RefreshWithContext(ctx, current)
+}
+
+// CanRefresh implements RefreshReporter. It is always true, since the
+// provider may supply new credentials even when the current ones are
+// static.
+func (p *ProviderAuth) CanRefresh() bool {
+	return true
+}
+
+// Type returns the type of the current credentials
+func (p *ProviderAuth) Type() AuthType {
+	return p.Authenticator().Type()
+}
+
+// SetHTTPClient implements HTTPAuthenticator
+func (p *ProviderAuth) SetHTTPClient(client utils.HTTPClient) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.httpClient = client
+	if a, ok := p.current.(HTTPAuthenticator); ok {
+		a.SetHTTPClient(client)
+	}
+}
+
+// SetTransport implements TransportAuthenticator
+func (p *ProviderAuth) SetTransport(transport http.RoundTripper) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.transport = transport
+	if a, ok := p.current.(TransportAuthenticator); ok {
+		a.SetTransport(transport)
+	}
+}
+
+// ObserveResponse implements ResponseObserver
+func (p *ProviderAuth) ObserveResponse(resp *http.Response) {
+	if observer, ok := p.Authenticator().(ResponseObserver); ok {
+		observer.ObserveResponse(resp)
+	}
+}
+
+// Validate implements Validator for authenticators that can check their
+// credentials
+func (p *ProviderAuth) Validate(ctx context.Context) error {
+	if validator, ok := p.Authenticator().(Validator); ok {
+		return validator.Validate(ctx)
+	}
+	return nil
+}
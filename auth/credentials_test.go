@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("TEST_DOKAN_BASE_URL", "https://example.com")
+	t.Setenv("TEST_DOKAN_CONSUMER_KEY", "ck_123")
+	t.Setenv("TEST_DOKAN_CONSUMER_SECRET", "cs_456")
+	t.Setenv("TEST_DOKAN_KEYS_IN_QUERY_STRING", "true")
+
+	provider := NewEnvProvider()
+	provider.SetPrefix("TEST_DOKAN_")
+	config, err := provider.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("Credentials() returned error: %v", err)
+	}
+
+	expected := Config{
+		Type:              AuthTypeWooCommerceKeys,
+		BaseURL:           "https://example.com",
+		ConsumerKey:       "ck_123",
+		ConsumerSecret:    "cs_456",
+		KeysInQueryString: true,
+	}
+	if config != expected {
+		t.Errorf("Expected %+v, got %+v", expected, config)
+	}
+
+	provider.SetPrefix("MISSING_DOKAN_")
+	if _, err := provider.Credentials(context.Background()); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Expected ErrNoCredentials, got %v", err)
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "credentials")
+	os.WriteFile(yamlPath, []byte(`# Dokan credentials
+default:
+  base_url: https://example.com
+  username: admin
+  password: "p#ss \"word\""
+
+"vendor":
+  type: application_password # WordPress 5.6+
+  username: vendor
+  password: 'abcd efgh ijkl mnop qrst uvwx'
+`), 0o600)
+	jsonPath := filepath.Join(dir, "credentials.json")
+	os.WriteFile(jsonPath, []byte(`{"staging": {"base_url": "https://staging.example.com", "token": "abc"}}`), 0o600)
+
+	tests := []struct {
+		path     string
+		profile  string
+		expected Config
+	}{
+		{yamlPath, "default", Config{Type: AuthTypeBasic, BaseURL: "https://example.com", Username: "admin", Password: `p#ss "word"`}},
+		{yamlPath, "vendor", Config{Type: AuthTypeApplicationPassword, Username: "vendor", Password: "abcd efgh ijkl mnop qrst uvwx"}},
+		{jsonPath, "staging", Config{Type: AuthTypeJWT, BaseURL: "https://staging.example.com", Token: "abc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			config, err := NewFileProvider(tt.path, tt.profile).Credentials(context.Background())
+			if err != nil {
+				t.Fatalf("Credentials() returned error: %v", err)
+			}
+			if config != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, config)
+			}
+		})
+	}
+
+	if _, err := NewFileProvider(yamlPath, "missing").Credentials(context.Background()); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Expected ErrNoCredentials for a missing profile, got %v", err)
+	}
+	if _, err := NewFileProvider(filepath.Join(dir, "missing"), "").Credentials(context.Background()); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Expected ErrNoCredentials for a missing file, got %v", err)
+	}
+
+	invalid := map[string]string{
+		"yaml unknown key":  "default:\n  username: admin\n  pasword: secret\n",
+		"yaml nested":       "default:\n  auth:\n    username: admin\n",
+		"yaml unterminated": "default:\n  password: \"unterminated\n",
+		"json unknown key":  `{"default": {"username": "admin", "pasword": "secret"}}`,
+		"json nested":       `{"default": {"auth": {"username": "admin"}}}`,
+		"json unterminated": `{"default": {"password": "secret}}`,
+	}
+	for name, content := range invalid {
+		t.Run(name, func(t *testing.T) {
+			invalidPath := filepath.Join(dir, "invalid")
+			os.WriteFile(invalidPath, []byte(content), 0o600)
+			if _, err := NewFileProvider(invalidPath, "").Credentials(context.Background()); err == nil || errors.Is(err, ErrNoCredentials) {
+				t.Errorf("Expected a parse error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestChainProvider(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "dokan_token"), []byte("secret-token\n"), 0o600)
+
+	secrets := NewSecretDirProvider(dir)
+	secrets.SetPrefix("dokan_")
+	env := NewEnvProvider()
+	env.SetPrefix("MISSING_DOKAN_")
+
+	config, err := NewChainProvider(env, secrets, NewFileProvider(filepath.Join(dir, "missing"), "")).Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("Credentials() returned error: %v", err)
+	}
+	if config.Type != AuthTypeJWT || config.Token != "secret-token" {
+		t.Errorf("Expected the token from the secret directory, got %+v", config)
+	}
+
+	if _, err := NewChainProvider(env).Credentials(context.Background()); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Expected ErrNoCredentials, got %v", err)
+	}
+}
+
+func TestProviderAuth_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "username"), []byte("admin\n"), 0o600)
+	os.WriteFile(filepath.Join(dir, "password"), []byte("old\n"), 0o600)
+
+	auth, err := NewProviderAuth(context.Background(), NewSecretDirProvider(dir), "https://example.com")
+	if err != nil {
+		t.Fatalf("NewProviderAuth() returned error: %v", err)
+	}
+	auth.SetReloadInterval(0)
+	if auth.Type() != AuthTypeBasic {
+		t.Errorf("Expected type %v, got %v", AuthTypeBasic, auth.Type())
+	}
+
+	// Unchanged credentials cannot be refreshed
+	if err := auth.Refresh(); err == nil {
+		t.Error("Refresh() should return error when the credentials have not changed")
+	}
+
+	os.WriteFile(filepath.Join(dir, "password"), []byte("new\n"), 0o600)
+
+	// Without a reload interval, the new password is only read on refresh
+	req, _ := http.NewRequest("GET", "https://example.com", nil)
+	auth.Authenticate(req)
+	if _, password, _ := req.BasicAuth(); password != "old" {
+		t.Errorf("Expected the old password before refreshing, got '%s'", password)
+	}
+
+	if err := auth.Refresh(); err != nil {
+		t.Fatalf("Refresh() returned error: %v", err)
+	}
+	req, _ = http.NewRequest("GET", "https://example.com", nil)
+	auth.Authenticate(req)
+	if _, password, _ := req.BasicAuth(); password != "new" {
+		t.Errorf("Expected the rotated password, got '%s'", password)
+	}
+
+	// A failing provider keeps the previous credentials
+	os.Remove(filepath.Join(dir, "username"))
+	os.Remove(filepath.Join(dir, "password"))
+	if err := auth.Refresh(); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Expected ErrNoCredentials, got %v", err)
+	}
+	if auth.Config().Password != "new" {
+		t.Errorf("Expected the previous credentials to be kept, got %+v", auth.Config())
+	}
+
+	// Failures while authenticating are reported, not dropped
+	var reloadErr error
+	auth.SetOnReloadError(func(err error) { reloadErr = err })
+	auth.SetReloadInterval(time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	req, _ = http.NewRequest("GET", "https://example.com", nil)
+	if err := auth.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() returned error: %v", err)
+	}
+	if !errors.Is(reloadErr, ErrNoCredentials) {
+		t.Errorf("Expected the reload error to be reported, got %v", reloadErr)
+	}
+	if _, password, _ := req.BasicAuth(); password != "new" {
+		t.Errorf("Expected the previous password, got '%s'", password)
+	}
+}
@@ -0,0 +1,266 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
+)
+
+// Endpoints of the JWT Authentication for WP REST API plugin
+const (
+	JWTTokenPath    = "/wp-json/jwt-auth/v1/token"
+	JWTValidatePath = "/wp-json/jwt-auth/v1/token/validate"
+	JWTRefreshPath  = "/wp-json/jwt-auth/v1/token/refresh"
+)
+
+// loginTimeout bounds the requests made to obtain a token, within the
+// deadline of the request that needs it
+const loginTimeout = 30 * time.Second
+
+// JWTLoginAuth implements JWT Authentication with tokens obtained from the
+// JWT Auth plugin endpoints. It logs in with the username and password to
+// get a token, or exchanges the refresh token for one when set, reads the
+// expiration from the token's exp claim, and gets a new token when it
+// expires or the server rejects it with 401 or 403.
+//
+// Like JWTAuth, which it builds on, it is safe for concurrent use.
+type JWTLoginAuth struct {
+	*JWTAuth
+	baseURL    string
+	username   string
+	password   string
+	httpClient utils.HTTPClient
+}
+
+// NewJWTLoginAuth creates a JWTLoginAuth authenticator for the site at
+// baseURL. No request is made until a token is needed.
+func NewJWTLoginAuth(baseURL, username, password string) *JWTLoginAuth {
+	a := &JWTLoginAuth{
+		JWTAuth:    NewJWTAuth("", time.Time{}),
+		baseURL:    baseURL,
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Timeout: loginTimeout},
+	}
+	a.obtain = a.obtainToken
+	return a
+}
+
+// SetHTTPClient implements HTTPAuthenticator
+func (a *JWTLoginAuth) SetHTTPClient(client utils.HTTPClient) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.httpClient = client
+}
+
+// SetToken updates the token, reading its expiration from the exp claim when
+// expiresAt is zero
+func (a *JWTLoginAuth) SetToken(token string, expiresAt time.Time) {
+	if expiresAt.IsZero() {
+		expiresAt, _ = JWTExpiry(token)
+	}
+	a.JWTAuth.SetToken(token, expiresAt)
+}
+
+// IsValid checks if the current token is still valid. A token can be
+// obtained on demand as long as a username and password are set.
+func (a *JWTLoginAuth) IsValid() bool {
+	return a.JWTAuth.IsValid() || (a.username != "" && a.password != "")
+}
+
+// CanRefresh implements RefreshReporter. A token can be obtained with the
+// username and password or with a refresh token.
+func (a *JWTLoginAuth) CanRefresh() bool {
+	return (a.username != "" && a.password != "") || a.getRefreshToken() != ""
+}
+
+// ObserveResponse implements ResponseObserver. It discards the token of
+// requests rejected by the JWT plugin, so that the next request gets a new
+// one.
+func (a *JWTLoginAuth) ObserveResponse(resp *http.Response) {
+	if resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden {
+		return
+	}
+
+	// Dokan also answers 403 to vendors lacking permissions, which a new
+	// token would not fix
+	if resp.StatusCode == http.StatusForbidden && !isJWTError(resp) {
+		return
+	}
+
+	token := strings.TrimPrefix(resp.Request.Header.Get("Authorization"), "Bearer ")
+	if token != "" {
+		a.invalidate(token)
+	}
+}
+
+// Validate checks the current token against the validate endpoint
+func (a *JWTLoginAuth) Validate(ctx context.Context) error {
+	token := a.GetToken()
+	if token == "" {
+		return fmt.Errorf("JWT token is required")
+	}
+
+	a.mu.RLock()
+	httpClient := a.httpClient
+	a.mu.RUnlock()
+
+	_, err := utils.MakeRequest(ctx, httpClient, a.baseURL, utils.RequestOptions{
+		Method:  http.MethodPost,
+		Path:    JWTValidatePath,
+		Headers: map[string]string{"Authorization": "Bearer " + token},
+	})
+	if err != nil {
+		return fmt.Errorf("JWT token validation failed: %w", err)
+	}
+	return nil
+}
+
+// obtainToken gets a new token, from the refresh token if there is one and
+// otherwise by logging in
+func (a *JWTLoginAuth) obtainToken(ctx context.Context) (string, time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, loginTimeout)
+	defer cancel()
+
+	if refreshToken := a.getRefreshToken(); refreshToken != "" {
+		token, err := a.requestToken(ctx, JWTRefreshPath, map[string]string{"refresh_token": refreshToken})
+		if err == nil || a.username == "" || a.password == "" {
+			return token.Token, token.expiresAt(), err
+		}
+	}
+
+	if a.username == "" || a.password == "" {
+		return "", time.Time{}, fmt.Errorf("username and password are required for JWT login")
+	}
+
+	token, err := a.requestToken(ctx, JWTTokenPath, map[string]string{
+		"username": a.username,
+		"password": a.password,
+	})
+	return token.Token, token.expiresAt(), err
+}
+
+// jwtToken is a token issued by the JWT plugin
+type jwtToken struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// expiresAt returns the expiration time of the token, or the zero time if
+// it cannot be read
+func (t jwtToken) expiresAt() time.Time {
+	expiresAt, _ := JWTExpiry(t.Token)
+	return expiresAt
+}
+
+// requestToken requests a token from the JWT plugin, storing the refresh
+// token it returns, if any
+func (a *JWTLoginAuth) requestToken(ctx context.Context, path string, body map[string]string) (jwtToken, error) {
+	a.mu.RLock()
+	httpClient := a.httpClient
+	a.mu.RUnlock()
+
+	resp, err := utils.MakeRequest(ctx, httpClient, a.baseURL, utils.RequestOptions{
+		Method: http.MethodPost,
+		Path:   path,
+		Body:   body,
+	})
+	if err != nil {
+		return jwtToken{}, fmt.Errorf("JWT login failed: %w", err)
+	}
+
+	// Some versions of the plugin wrap the token in a data object
+	var result struct {
+		jwtToken
+		Data jwtToken `json:"data"`
+	}
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return jwtToken{}, fmt.Errorf("failed to decode JWT login response: %w", err)
+	}
+
+	token := result.jwtToken
+	if token.Token == "" {
+		token = result.Data
+	}
+	if token.Token == "" {
+		return jwtToken{}, fmt.Errorf("JWT login response contains no token")
+	}
+
+	if token.RefreshToken != "" {
+		a.SetRefreshToken(token.RefreshToken)
+	}
+	return token, nil
+}
+
+// errorCode returns the code of a WordPress REST API error response, or ""
+// if it is not one. The body is left intact.
+func errorCode(resp *http.Response) string {
+	if resp.Body == nil {
+		return ""
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var apiErr struct {
+		Code string `json:"code"`
+	}
+	if json.Unmarshal(body, &apiErr) != nil {
+		return ""
+	}
+	return apiErr.Code
+}
+
+// hasErrorCode reports whether resp is a WordPress REST API error with the
+// given code
+func hasErrorCode(resp *http.Response, code string) bool {
+	return errorCode(resp) == code
+}
+
+// isJWTError reports whether resp is an error from the JWT plugin, whose
+// codes start with "jwt_auth"
+func isJWTError(resp *http.Response) bool {
+	return strings.HasPrefix(strings.TrimPrefix(errorCode(resp), "["), "jwt_auth")
+}
+
+// JWTExpiry returns the expiration time in the exp claim of a JWT token, or
+// the zero time if the claim is absent. The signature is not verified.
+func JWTExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("malformed JWT token")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed JWT payload: %w", err)
+	}
+
+	var claims struct {
+		Exp json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("malformed JWT claims: %w", err)
+	}
+	if claims.Exp == "" {
+		return time.Time{}, nil
+	}
+
+	exp, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed JWT exp claim: %w", err)
+	}
+	return time.Unix(int64(exp), 0), nil
+}
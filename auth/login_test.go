@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// makeJWT returns an unsigned JWT token with the given subject and
+// expiration
+func makeJWT(subject string, exp time.Time) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":%q,"exp":%d}`, subject, exp.Unix())))
+	return header + "." + payload + ".signature"
+}
+
+func TestJWTLoginAuth(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case JWTTokenPath:
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["username"] != "vendor" || body["password"] != "secret" {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"code": "[jwt_auth] incorrect_password", "message": "Incorrect password", "data": {"status": 403}}`))
+				return
+			}
+			logins++
+			json.NewEncoder(w).Encode(map[string]string{"token": makeJWT(fmt.Sprint(logins), exp)})
+		case JWTValidatePath:
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte(`{"code": "jwt_auth_valid_token", "data": {"status": 200}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	auth := NewJWTLoginAuth(server.URL, "vendor", "secret")
+	if !auth.IsValid() {
+		t.Error("JWT login auth with credentials should be valid")
+	}
+
+	req, _ := http.NewRequest("GET", server.URL+"/wp-json/dokan/v1/products", nil)
+	if err := auth.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() returned error: %v", err)
+	}
+	if header := req.Header.Get("Authorization"); header != "Bearer "+makeJWT("1", exp) {
+		t.Errorf("Expected the token from the login, got '%s'", header)
+	}
+	if !auth.GetExpiresAt().Equal(exp) {
+		t.Errorf("Expected expiry %v from the exp claim, got %v", exp, auth.GetExpiresAt())
+	}
+	if err := auth.Validate(context.Background()); err != nil {
+		t.Errorf("Validate() returned error: %v", err)
+	}
+
+	// A permission error does not discard the token
+	auth.ObserveResponse(&http.Response{
+		StatusCode: http.StatusForbidden,
+		Request:    req,
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{"code": "dokan_rest_forbidden"}`))),
+	})
+	if logins != 1 || auth.GetToken() != makeJWT("1", exp) {
+		t.Fatal("Expected the token to be kept after a permission error")
+	}
+
+	// A rejected token triggers a new login
+	auth.ObserveResponse(&http.Response{StatusCode: http.StatusUnauthorized, Request: req, Body: http.NoBody})
+	req, _ = http.NewRequest("GET", server.URL+"/wp-json/dokan/v1/products", nil)
+	if err := auth.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() returned error: %v", err)
+	}
+	if header := req.Header.Get("Authorization"); header != "Bearer "+makeJWT("2", exp) || logins != 2 {
+		t.Errorf("Expected a new login after a 401, got '%s' after %d logins", header, logins)
+	}
+
+	wrong := NewJWTLoginAuth(server.URL, "vendor", "wrong")
+	req, _ = http.NewRequest("GET", server.URL+"/wp-json/dokan/v1/products", nil)
+	if err := wrong.Authenticate(req); err == nil {
+		t.Error("Authenticate() should return error for wrong credentials")
+	}
+}
+
+func TestJWTLoginAuth_RefreshToken(t *testing.T) {
+	exp := time.Now().Add(time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != JWTRefreshPath || body["refresh_token"] != "refresh-1" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(fmt.Sprintf(`{"success": true, "data": {"token": %q, "refresh_token": "refresh-2"}}`, makeJWT("refreshed", exp))))
+	}))
+	defer server.Close()
+
+	auth, err := NewAuthenticator(Config{
+		Type:         AuthTypeJWT,
+		BaseURL:      server.URL,
+		Token:        makeJWT("expired", time.Now().Add(-time.Hour)),
+		RefreshToken: "refresh-1",
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator() returned error: %v", err)
+	}
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if err := auth.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() returned error: %v", err)
+	}
+	if header := req.Header.Get("Authorization"); header != "Bearer "+makeJWT("refreshed", exp) {
+		t.Errorf("Expected the refreshed token, got '%s'", header)
+	}
+	if refreshToken := auth.(*JWTLoginAuth).getRefreshToken(); refreshToken != "refresh-2" {
+		t.Errorf("Expected the new refresh token to be stored, got '%s'", refreshToken)
+	}
+}
+
+func TestJWTLoginAuth_RequestContext(t *testing.T) {
+	exp := time.Now().Add(time.Hour)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		json.NewEncoder(w).Encode(map[string]string{"token": makeJWT("vendor", exp)})
+	}))
+	defer server.Close()
+	defer close(release)
+
+	auth := NewJWTLoginAuth(server.URL, "vendor", "secret")
+
+	// The login triggered by a request is aborted with its context
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/wp-json/dokan/v1/products", nil)
+
+	start := time.Now()
+	if err := auth.Authenticate(req); err == nil {
+		t.Fatal("Authenticate() should return error when the request context expires")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the login to be aborted with the request, took %v", elapsed)
+	}
+
+	// An abandoned login does not count as a failure for the next request
+	auth.mu.RLock()
+	lastFailure := auth.lastFailure
+	auth.mu.RUnlock()
+	if !lastFailure.IsZero() {
+		t.Error("Expected an abandoned login not to be recorded as a failure")
+	}
+}
+
+func TestJWTExpiry(t *testing.T) {
+	exp := time.Unix(1900000000, 0)
+	if got, err := JWTExpiry(makeJWT("user", exp)); err != nil || !got.Equal(exp) {
+		t.Errorf("JWTExpiry() = %v, %v, expected %v", got, err, exp)
+	}
+
+	if _, err := JWTExpiry("not-a-jwt"); err == nil {
+		t.Error("JWTExpiry() should return error for a malformed token")
+	}
+}
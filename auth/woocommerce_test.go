@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestWooCommerceKeysAuth_HTTPS(t *testing.T) {
+	auth := NewWooCommerceKeysAuth("ck_123", "cs_456")
+
+	req, _ := http.NewRequest("GET", "https://example.com/wp-json/dokan/v1/products?page=2", nil)
+	if err := auth.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() returned error: %v", err)
+	}
+	if username, password, ok := req.BasicAuth(); !ok || username != "ck_123" || password != "cs_456" {
+		t.Errorf("Expected Basic Auth with the keys, got %q %q", username, password)
+	}
+
+	auth.SetQueryString(true)
+	req, _ = http.NewRequest("GET", "https://example.com/wp-json/dokan/v1/products?page=2", nil)
+	if err := auth.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() returned error: %v", err)
+	}
+	if expected := "consumer_key=ck_123&consumer_secret=cs_456&page=2"; req.URL.RawQuery != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, req.URL.RawQuery)
+	}
+}
+
+func TestWooCommerceKeysAuth_OAuth(t *testing.T) {
+	auth := NewWooCommerceKeysAuth("ck_123", "cs_456")
+	auth.SetSignatureMethod(OAuthHMACSHA1)
+	auth.now = func() time.Time { return time.Unix(1700000000, 0) }
+	auth.nonce = func() (string, error) { return "abc123", nil }
+
+	req, _ := http.NewRequest("GET", "http://Example.com:80/wp-json/dokan/v1/products?search=blue%20mug&page=2", nil)
+	if err := auth.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() returned error: %v", err)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Error("Expected no credentials in the headers over HTTP")
+	}
+
+	query := req.URL.Query()
+	if query.Get("consumer_secret") != "" {
+		t.Error("Expected the consumer secret not to be sent over HTTP")
+	}
+
+	baseString := "GET&http%3A%2F%2Fexample.com%2Fwp-json%2Fdokan%2Fv1%2Fproducts&" +
+		"oauth_consumer_key%3Dck_123%26oauth_nonce%3Dabc123%26oauth_signature_method%3DHMAC-SHA1" +
+		"%26oauth_timestamp%3D1700000000%26page%3D2%26search%3Dblue%2520mug"
+	mac := hmac.New(sha1.New, []byte("cs_456&"))
+	mac.Write([]byte(baseString))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	if signature := query.Get("oauth_signature"); signature != expected {
+		t.Errorf("Expected signature '%s', got '%s'", expected, signature)
+	}
+	if query.Get("search") != "blue mug" || query.Get("page") != "2" {
+		t.Errorf("Expected the original query to be kept, got '%s'", req.URL.RawQuery)
+	}
+
+	// A retry replaces the previous signature
+	auth.nonce = func() (string, error) { return "def456", nil }
+	if err := auth.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() returned error: %v", err)
+	}
+	query, _ = url.ParseQuery(req.URL.RawQuery)
+	if len(query["oauth_nonce"]) != 1 || query.Get("oauth_nonce") != "def456" || len(query["oauth_signature"]) != 1 {
+		t.Errorf("Expected a single fresh signature, got '%s'", req.URL.RawQuery)
+	}
+}
+
+func TestNewAuthenticator_WooCommerceKeys(t *testing.T) {
+	auth, err := NewAuthenticator(Config{
+		Type:                 AuthTypeWooCommerceKeys,
+		ConsumerKey:          "ck_123",
+		ConsumerSecret:       "cs_456",
+		OAuthSignatureMethod: OAuthHMACSHA1,
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator() returned error: %v", err)
+	}
+	if auth.Type() != AuthTypeWooCommerceKeys {
+		t.Errorf("Expected type %v, got %v", AuthTypeWooCommerceKeys, auth.Type())
+	}
+
+	if _, err := NewAuthenticator(Config{Type: AuthTypeWooCommerceKeys, ConsumerKey: "ck_123"}); err == nil {
+		t.Error("NewAuthenticator() should return error for WooCommerce auth without a secret")
+	}
+}
@@ -0,0 +1,321 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
+)
+
+// WordPress endpoints used to log in and check credentials
+const (
+	WPUsersMePath   = "/wp-json/wp/v2/users/me"
+	WPLoginPath     = "/wp-login.php"
+	WPRestNoncePath = "/wp-admin/admin-ajax.php?action=rest-nonce"
+)
+
+// ApplicationPasswordAuth implements authentication with WordPress
+// Application Passwords, which any user, such as a vendor, can create from
+// their profile without sharing their login password
+type ApplicationPasswordAuth struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient utils.HTTPClient
+}
+
+// NewApplicationPasswordAuth creates a new ApplicationPasswordAuth
+// authenticator. The spaces WordPress shows in application passwords, as in
+// "abcd efgh ijkl mnop qrst uvwx", are stripped.
+func NewApplicationPasswordAuth(baseURL, username, password string) *ApplicationPasswordAuth {
+	return &ApplicationPasswordAuth{
+		baseURL:    baseURL,
+		username:   username,
+		password:   strings.Join(strings.Fields(password), ""),
+		httpClient: &http.Client{Timeout: loginTimeout},
+	}
+}
+
+// Authenticate adds the application password to the request with Basic
+// Authentication
+func (a *ApplicationPasswordAuth) Authenticate(req *http.Request) error {
+	if !a.IsValid() {
+		return fmt.Errorf("username and application password are required")
+	}
+
+	credentials := a.username + ":" + a.password
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(credentials)))
+	return nil
+}
+
+// IsValid checks if the username and application password are set
+func (a *ApplicationPasswordAuth) IsValid() bool {
+	return a.username != "" && a.password != ""
+}
+
+// Refresh is a no-op for application passwords
+func (a *ApplicationPasswordAuth) Refresh() error {
+	return nil
+}
+
+// CanRefresh implements RefreshReporter. Application passwords are static.
+func (a *ApplicationPasswordAuth) CanRefresh() bool {
+	return false
+}
+
+// Type returns the authentication type
+func (a *ApplicationPasswordAuth) Type() AuthType {
+	return AuthTypeApplicationPassword
+}
+
+// SetHTTPClient implements HTTPAuthenticator
+func (a *ApplicationPasswordAuth) SetHTTPClient(client utils.HTTPClient) {
+	a.httpClient = client
+}
+
+// Validate checks that the application password belongs to an existing
+// user by fetching the current user
+func (a *ApplicationPasswordAuth) Validate(ctx context.Context) error {
+	return validateUser(ctx, a.httpClient, a.baseURL, a)
+}
+
+// CookieAuth implements WordPress cookie authentication. It logs in through
+// wp-login.php with the username and password, keeps the session cookies in
+// a cookie jar and sends them with the REST nonce in the X-WP-Nonce header,
+// which WordPress requires to accept cookies on REST requests.
+//
+// Logging in needs its own HTTP client, since the session cookies are set
+// on a redirect that must not be followed; see SetTransport. It is safe for
+// concurrent use: simultaneous requests wait for a single login.
+type CookieAuth struct {
+	baseURL  string
+	username string
+	password string
+
+	mu          sync.Mutex
+	jar         *cookiejar.Jar
+	nonce       string
+	loginClient *http.Client
+	httpClient  utils.HTTPClient
+}
+
+// NewCookieAuth creates a new CookieAuth authenticator for the site at
+// baseURL. No request is made until the first one is authenticated.
+func NewCookieAuth(baseURL, username, password string) *CookieAuth {
+	jar, _ := cookiejar.New(nil)
+	return &CookieAuth{
+		baseURL:  baseURL,
+		username: username,
+		password: password,
+		jar:      jar,
+		loginClient: &http.Client{
+			Jar:     jar,
+			Timeout: loginTimeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		httpClient: &http.Client{Timeout: loginTimeout},
+	}
+}
+
+// SetTransport implements TransportAuthenticator. It sets the transport
+// used to log in, which keeps its own cookie jar and does not follow
+// redirects.
+func (c *CookieAuth) SetTransport(transport http.RoundTripper) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loginClient.Transport = transport
+}
+
+// SetHTTPClient implements HTTPAuthenticator. It sets the client used by
+// Validate, whose requests are authenticated like any other.
+func (c *CookieAuth) SetHTTPClient(client utils.HTTPClient) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.httpClient = client
+}
+
+// Jar returns the cookie jar holding the session cookies
+func (c *CookieAuth) Jar() http.CookieJar {
+	return c.jar
+}
+
+// Authenticate adds the session cookies and the REST nonce to the request,
+// logging in first if needed
+func (c *CookieAuth) Authenticate(req *http.Request) error {
+	if !c.IsValid() {
+		return fmt.Errorf("username and password are required for cookie auth")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.nonce == "" {
+		if err := c.login(req.Context()); err != nil {
+			return err
+		}
+	}
+
+	for _, cookie := range c.jar.Cookies(req.URL) {
+		req.AddCookie(cookie)
+	}
+	req.Header.Set("X-WP-Nonce", c.nonce)
+	return nil
+}
+
+// IsValid checks if the username and password are set
+func (c *CookieAuth) IsValid() bool {
+	return c.username != "" && c.password != ""
+}
+
+// Refresh logs in again
+func (c *CookieAuth) Refresh() error {
+	return c.RefreshContext(context.Background())
+}
+
+// RefreshContext implements ContextRefresher. It logs in again, giving up
+// when ctx is done.
+func (c *CookieAuth) RefreshContext(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.login(ctx)
+}
+
+// CanRefresh implements RefreshReporter. Logging in again needs the
+// username and password.
+func (c *CookieAuth) CanRefresh() bool {
+	return c.IsValid()
+}
+
+// Type returns the authentication type
+func (c *CookieAuth) Type() AuthType {
+	return AuthTypeCookie
+}
+
+// ObserveResponse implements ResponseObserver. It discards the session when
+// WordPress rejects the cookies or the nonce, so that the next request logs
+// in again.
+func (c *CookieAuth) ObserveResponse(resp *http.Response) {
+	if resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden {
+		return
+	}
+
+	nonce := resp.Request.Header.Get("X-WP-Nonce")
+	if resp.StatusCode == http.StatusForbidden && !hasErrorCode(resp, "rest_cookie_invalid_nonce") {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.nonce == nonce {
+		c.nonce = ""
+	}
+}
+
+// Validate checks that the session belongs to an existing user by fetching
+// the current user, logging in first if needed
+func (c *CookieAuth) Validate(ctx context.Context) error {
+	c.mu.Lock()
+	httpClient := c.httpClient
+	c.mu.Unlock()
+	return validateUser(ctx, httpClient, c.baseURL, c)
+}
+
+// login logs in through wp-login.php and fetches a REST nonce. c.mu must be
+// held.
+func (c *CookieAuth) login(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, loginTimeout)
+	defer cancel()
+
+	siteURL, err := url.Parse(strings.TrimSuffix(c.baseURL, "/"))
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %w", err)
+	}
+	loginURL := siteURL.JoinPath(WPLoginPath)
+
+	// WordPress refuses logins from clients that do not keep cookies
+	c.jar.SetCookies(loginURL, []*http.Cookie{{Name: "wordpress_test_cookie", Value: "WP%20Cookie%20check", Path: "/"}})
+
+	form := url.Values{
+		"log":         {c.username},
+		"pwd":         {c.password},
+		"rememberme":  {"forever"},
+		"wp-submit":   {"Log In"},
+		"testcookie":  {"1"},
+		"redirect_to": {siteURL.JoinPath("/wp-admin/").String()},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, loginURL.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create login request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.loginClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cookie login failed: %w", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	loggedIn := false
+	for _, cookie := range c.jar.Cookies(siteURL) {
+		if strings.HasPrefix(cookie.Name, "wordpress_logged_in_") {
+			loggedIn = true
+		}
+	}
+	if !loggedIn {
+		return fmt.Errorf("cookie login failed: invalid username or password")
+	}
+
+	// The nonce is tied to the session, so it is fetched with its cookies
+	nonceURL := siteURL.String() + WPRestNoncePath
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, nonceURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create nonce request: %w", err)
+	}
+
+	resp, err = c.loginClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch REST nonce: %w", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to fetch REST nonce: %w", err)
+	}
+
+	nonce := strings.TrimSpace(string(body))
+	if resp.StatusCode != http.StatusOK || nonce == "" || nonce == "0" || nonce == "-1" {
+		return fmt.Errorf("failed to fetch REST nonce: HTTP %d", resp.StatusCode)
+	}
+
+	c.nonce = nonce
+	return nil
+}
+
+// validateUser fetches the current user with requests authenticated by auth
+func validateUser(ctx context.Context, client utils.HTTPClient, baseURL string, auth Authenticator) error {
+	authenticated := utils.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+		if err := auth.Authenticate(req); err != nil {
+			return nil, err
+		}
+		return client.Do(req)
+	})
+
+	_, err := utils.MakeRequest(ctx, authenticated, baseURL, utils.RequestOptions{
+		Method: http.MethodGet,
+		Path:   WPUsersMePath,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to validate credentials: %w", err)
+	}
+	return nil
+}
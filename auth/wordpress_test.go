@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newWordPressServer returns a server that mimics the WordPress login and
+// REST endpoints for the user "vendor"
+func newWordPressServer(t *testing.T, logins *int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case WPLoginPath:
+			if _, err := r.Cookie("wordpress_test_cookie"); err != nil {
+				w.Write([]byte("Cookies are blocked"))
+				return
+			}
+			if r.FormValue("log") != "vendor" || r.FormValue("pwd") != "secret" {
+				w.Write([]byte("<form>Incorrect password</form>"))
+				return
+			}
+			*logins++
+			http.SetCookie(w, &http.Cookie{Name: "wordpress_sec_abc", Value: "auth", Path: "/wp-admin"})
+			http.SetCookie(w, &http.Cookie{Name: "wordpress_logged_in_abc", Value: "session", Path: "/"})
+			http.Redirect(w, r, "/wp-admin/", http.StatusFound)
+		case "/wp-admin/admin-ajax.php":
+			if _, err := r.Cookie("wordpress_sec_abc"); err != nil || r.URL.Query().Get("action") != "rest-nonce" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte("0"))
+				return
+			}
+			w.Write([]byte("nonce123"))
+		case WPUsersMePath:
+			username, password, ok := r.BasicAuth()
+			if ok && username == "vendor" && password == "abcdefghijklmnopqrstuvwx" {
+				w.Write([]byte(`{"id": 7, "slug": "vendor"}`))
+				return
+			}
+			if cookie, err := r.Cookie("wordpress_logged_in_abc"); err == nil && cookie.Value == "session" {
+				if r.Header.Get("X-WP-Nonce") != "nonce123" {
+					w.WriteHeader(http.StatusForbidden)
+					w.Write([]byte(`{"code": "rest_cookie_invalid_nonce", "message": "Cookie check failed"}`))
+					return
+				}
+				w.Write([]byte(`{"id": 7, "slug": "vendor"}`))
+				return
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code": "rest_not_logged_in", "message": "You are not currently logged in."}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestApplicationPasswordAuth(t *testing.T) {
+	server := newWordPressServer(t, new(int))
+	defer server.Close()
+
+	auth := NewApplicationPasswordAuth(server.URL, "vendor", "abcd efgh ijkl mnop qrst uvwx")
+	if auth.password != "abcdefghijklmnopqrstuvwx" {
+		t.Errorf("Expected spaces to be stripped, got '%s'", auth.password)
+	}
+	if auth.Type() != AuthTypeApplicationPassword {
+		t.Errorf("Expected type %v, got %v", AuthTypeApplicationPassword, auth.Type())
+	}
+	if err := auth.Validate(context.Background()); err != nil {
+		t.Errorf("Validate() returned error: %v", err)
+	}
+
+	wrong := NewApplicationPasswordAuth(server.URL, "vendor", "wrong")
+	if err := wrong.Validate(context.Background()); err == nil {
+		t.Error("Validate() should return error for a wrong application password")
+	}
+}
+
+func TestCookieAuth(t *testing.T) {
+	logins := 0
+	server := newWordPressServer(t, &logins)
+	defer server.Close()
+
+	auth, err := NewAuthenticator(Config{
+		Type:     AuthTypeCookie,
+		BaseURL:  server.URL,
+		Username: "vendor",
+		Password: "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator() returned error: %v", err)
+	}
+	cookieAuth := auth.(*CookieAuth)
+
+	req, _ := http.NewRequest("GET", server.URL+WPUsersMePath, nil)
+	if err := auth.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() returned error: %v", err)
+	}
+	if req.Header.Get("X-WP-Nonce") != "nonce123" {
+		t.Errorf("Expected the REST nonce header, got '%s'", req.Header.Get("X-WP-Nonce"))
+	}
+	if cookie, err := req.Cookie("wordpress_logged_in_abc"); err != nil || cookie.Value != "session" {
+		t.Errorf("Expected the session cookie, got %v", req.Cookies())
+	}
+	if _, err := req.Cookie("wordpress_sec_abc"); err == nil {
+		t.Error("Expected the admin cookie not to be sent to the REST API")
+	}
+
+	if err := cookieAuth.Validate(context.Background()); err != nil {
+		t.Errorf("Validate() returned error: %v", err)
+	}
+
+	// A rejected nonce triggers a new login
+	cookieAuth.ObserveResponse(&http.Response{StatusCode: http.StatusUnauthorized, Request: req, Body: http.NoBody})
+	req, _ = http.NewRequest("GET", server.URL+WPUsersMePath, nil)
+	if err := auth.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() returned error: %v", err)
+	}
+	if logins != 2 {
+		t.Errorf("Expected a new login after a 401, got %d logins", logins)
+	}
+
+	wrong := NewCookieAuth(server.URL, "vendor", "wrong")
+	req, _ = http.NewRequest("GET", server.URL+WPUsersMePath, nil)
+	if err := wrong.Authenticate(req); err == nil {
+		t.Error("Authenticate() should return error for a wrong password")
+	}
+}
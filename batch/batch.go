@@ -0,0 +1,296 @@
+// Package batch runs create, update and delete operations against
+// WooCommerce-style /batch endpoints. Large inputs are split into chunks that
+// respect the server limit, chunks are sent with bounded concurrency, and
+// every input is mapped back to either the saved entity or its own error.
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/errors"
+	"github.com/diogenes-moreira/dokan-go-sdk/types"
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
+)
+
+// MaxChunkSize is the maximum number of operations WooCommerce accepts in a
+// single batch request
+const MaxChunkSize = 100
+
+// DefaultConcurrency is the number of chunks sent in parallel when
+// Options.Concurrency is not set
+const DefaultConcurrency = 4
+
+// Options controls how a batch is split and sent
+type Options struct {
+	// ChunkSize is the maximum number of operations (creates, updates and
+	// deletes combined) per request. Zero or values above MaxChunkSize use
+	// MaxChunkSize.
+	ChunkSize int
+
+	// Concurrency is the maximum number of chunks in flight. Zero uses
+	// DefaultConcurrency.
+	Concurrency int
+}
+
+// Sender sends a single chunk to the batch endpoint and returns the raw
+// per-item response
+type Sender[T any] func(ctx context.Context, req *types.BatchRequest[T]) (*types.BatchResponse[json.RawMessage], error)
+
+// Client is the interface the services use to make requests
+type Client interface {
+	MakeRequest(ctx context.Context, opts utils.RequestOptions) (*utils.Response, error)
+}
+
+// NewSender returns a Sender that posts each chunk to path through client,
+// reported as operation
+func NewSender[T any](client Client, path, operation string) Sender[T] {
+	return func(ctx context.Context, req *types.BatchRequest[T]) (*types.BatchResponse[json.RawMessage], error) {
+		opts := utils.RequestOptions{
+			Method:    http.MethodPost,
+			Path:      path,
+			Operation: operation,
+			Body:      req,
+		}
+
+		resp, err := client.MakeRequest(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send batch request: %w", err)
+		}
+
+		var batchResponse types.BatchResponse[json.RawMessage]
+		if err := utils.ParseJSON(resp.Body, &batchResponse); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		return &batchResponse, nil
+	}
+}
+
+// Operation identifies the kind of operation of an item
+type Operation string
+
+const (
+	OperationCreate Operation = "create"
+	OperationUpdate Operation = "update"
+	OperationDelete Operation = "delete"
+)
+
+// ItemResult is the outcome of a single input of a batch
+type ItemResult[R any] struct {
+	Operation Operation
+	// Index is the position of the input in the Create, Update or Delete
+	// slice of the request
+	Index int
+	// Item is the entity returned by the server, nil when Err is set
+	Item *R
+	// Err is the error reported for this item, or the error of the request
+	// that carried it
+	Err error
+}
+
+// Result holds one ItemResult per input, in the same order as the request
+type Result[R any] struct {
+	Create []ItemResult[R]
+	Update []ItemResult[R]
+	Delete []ItemResult[R]
+}
+
+// Failed returns the results that have an error
+func (r *Result[R]) Failed() []ItemResult[R] {
+	var failed []ItemResult[R]
+	for _, results := range [][]ItemResult[R]{r.Create, r.Update, r.Delete} {
+		for _, result := range results {
+			if result.Err != nil {
+				failed = append(failed, result)
+			}
+		}
+	}
+	return failed
+}
+
+// Err returns the errors of all failed items joined together, or nil when
+// every item succeeded
+func (r *Result[R]) Err() error {
+	var errs []error
+	for _, result := range r.Failed() {
+		errs = append(errs, fmt.Errorf("%s[%d]: %w", result.Operation, result.Index, result.Err))
+	}
+	return stderrors.Join(errs...)
+}
+
+// chunk holds the [start, end) ranges of each operation sent in one request
+type chunk struct {
+	create, update, delete [2]int
+}
+
+// Run splits req into chunks, sends them with up to opts.Concurrency
+// requests in flight and maps every input to its result. Requests that fail
+// as a whole do not stop the remaining chunks; their error is recorded on
+// each of their items and the errors are also returned joined together.
+func Run[T, R any](ctx context.Context, send Sender[T], req *types.BatchRequest[T], opts *Options) (*Result[R], error) {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.ChunkSize < 1 || o.ChunkSize > MaxChunkSize {
+		o.ChunkSize = MaxChunkSize
+	}
+	if o.Concurrency < 1 {
+		o.Concurrency = DefaultConcurrency
+	}
+
+	result := &Result[R]{}
+	if req == nil {
+		return result, nil
+	}
+
+	result.Create = newResults[R](OperationCreate, len(req.Create))
+	result.Update = newResults[R](OperationUpdate, len(req.Update))
+	result.Delete = newResults[R](OperationDelete, len(req.Delete))
+
+	chunks := split(len(req.Create), len(req.Update), len(req.Delete), o.ChunkSize)
+	errs := make([]error, len(chunks))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < o.Concurrency && i < len(chunks); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				errs[index] = sendChunk(ctx, send, req, chunks[index], result)
+			}
+		}()
+	}
+
+	for i := range chunks {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return result, stderrors.Join(errs...)
+}
+
+// sendChunk sends a single chunk and stores the outcome of its items in
+// result. Each chunk writes to disjoint ranges, so no locking is needed.
+func sendChunk[T, R any](ctx context.Context, send Sender[T], req *types.BatchRequest[T], c chunk, result *Result[R]) error {
+	chunkReq := &types.BatchRequest[T]{
+		Create: req.Create[c.create[0]:c.create[1]],
+		Update: req.Update[c.update[0]:c.update[1]],
+		Delete: req.Delete[c.delete[0]:c.delete[1]],
+	}
+
+	var (
+		resp *types.BatchResponse[json.RawMessage]
+		err  error
+	)
+	if err = ctx.Err(); err == nil {
+		resp, err = send(ctx, chunkReq)
+	}
+	if err == nil && resp == nil {
+		err = fmt.Errorf("empty batch response")
+	}
+
+	if err != nil {
+		fail(result.Create[c.create[0]:c.create[1]], err)
+		fail(result.Update[c.update[0]:c.update[1]], err)
+		fail(result.Delete[c.delete[0]:c.delete[1]], err)
+		return err
+	}
+
+	fill(result.Create[c.create[0]:c.create[1]], resp.Create)
+	fill(result.Update[c.update[0]:c.update[1]], resp.Update)
+	fill(result.Delete[c.delete[0]:c.delete[1]], resp.Delete)
+	return nil
+}
+
+// split divides the operations into chunks of at most size operations,
+// filling each chunk with creates, then updates, then deletes
+func split(creates, updates, deletes, size int) []chunk {
+	var (
+		chunks  []chunk
+		c, u, d int
+	)
+
+	for c < creates || u < updates || d < deletes {
+		room := size
+		var ch chunk
+
+		n := min(room, creates-c)
+		ch.create = [2]int{c, c + n}
+		c, room = c+n, room-n
+
+		n = min(room, updates-u)
+		ch.update = [2]int{u, u + n}
+		u, room = u+n, room-n
+
+		n = min(room, deletes-d)
+		ch.delete = [2]int{d, d + n}
+		d = d + n
+
+		chunks = append(chunks, ch)
+	}
+
+	return chunks
+}
+
+// newResults returns n results for op with their indexes set
+func newResults[R any](op Operation, n int) []ItemResult[R] {
+	results := make([]ItemResult[R], n)
+	for i := range results {
+		results[i].Operation = op
+		results[i].Index = i
+	}
+	return results
+}
+
+// fail records err on every result
+func fail[R any](results []ItemResult[R], err error) {
+	for i := range results {
+		results[i].Err = err
+	}
+}
+
+// fill decodes the raw items of a response into results. The server returns
+// the items in the order they were sent.
+func fill[R any](results []ItemResult[R], items []json.RawMessage) {
+	for i := range results {
+		if i >= len(items) {
+			results[i].Err = fmt.Errorf("missing %s result in batch response", results[i].Operation)
+			continue
+		}
+		results[i].Item, results[i].Err = decodeItem[R](items[i])
+	}
+}
+
+// decodeItem decodes a single item of a batch response, which is either the
+// saved entity or an object with an "error" field
+func decodeItem[R any](data json.RawMessage) (*R, error) {
+	var probe struct {
+		Error *errors.DokanError `json:"error"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse batch item: %w", err)
+	}
+
+	if probe.Error != nil {
+		if data, ok := probe.Error.Data.(map[string]interface{}); ok {
+			if status, ok := data["status"].(float64); ok {
+				probe.Error.StatusCode = int(status)
+			}
+		}
+		return nil, probe.Error
+	}
+
+	var item R
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, fmt.Errorf("failed to parse batch item: %w", err)
+	}
+	return &item, nil
+}
@@ -0,0 +1,180 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/errors"
+	"github.com/diogenes-moreira/dokan-go-sdk/types"
+)
+
+type item struct {
+	ID int `json:"id"`
+}
+
+// echoSender returns every create and update as saved, and every delete as
+// the deleted item
+func echoSender(ctx context.Context, req *types.BatchRequest[item]) (*types.BatchResponse[json.RawMessage], error) {
+	resp := &types.BatchResponse[json.RawMessage]{}
+	for _, it := range req.Create {
+		resp.Create = append(resp.Create, json.RawMessage(fmt.Sprintf(`{"id": %d}`, it.ID)))
+	}
+	for _, it := range req.Update {
+		resp.Update = append(resp.Update, json.RawMessage(fmt.Sprintf(`{"id": %d}`, it.ID)))
+	}
+	for _, id := range req.Delete {
+		resp.Delete = append(resp.Delete, json.RawMessage(fmt.Sprintf(`{"id": %d}`, id)))
+	}
+	return resp, nil
+}
+
+func TestSplit(t *testing.T) {
+	chunks := split(150, 40, 20, 100)
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks, got %d", len(chunks))
+	}
+
+	expected := []chunk{
+		{create: [2]int{0, 100}, update: [2]int{0, 0}, delete: [2]int{0, 0}},
+		{create: [2]int{100, 150}, update: [2]int{0, 40}, delete: [2]int{0, 10}},
+		{create: [2]int{150, 150}, update: [2]int{40, 40}, delete: [2]int{10, 20}},
+	}
+	for i, c := range chunks {
+		if c != expected[i] {
+			t.Errorf("chunk %d = %+v, expected %+v", i, c, expected[i])
+		}
+	}
+
+	if chunks := split(0, 0, 0, 100); len(chunks) != 0 {
+		t.Errorf("Expected no chunks for an empty batch, got %d", len(chunks))
+	}
+}
+
+func TestRun_ChunksAndOrder(t *testing.T) {
+	req := &types.BatchRequest[item]{}
+	for i := 0; i < 250; i++ {
+		req.Create = append(req.Create, item{ID: i})
+	}
+	for i := 0; i < 30; i++ {
+		req.Delete = append(req.Delete, 1000+i)
+	}
+
+	var (
+		mu       sync.Mutex
+		sizes    []int
+		inFlight int32
+		maxSeen  int32
+	)
+	send := func(ctx context.Context, chunk *types.BatchRequest[item]) (*types.BatchResponse[json.RawMessage], error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			seen := atomic.LoadInt32(&maxSeen)
+			if n <= seen || atomic.CompareAndSwapInt32(&maxSeen, seen, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		sizes = append(sizes, len(chunk.Create)+len(chunk.Update)+len(chunk.Delete))
+		mu.Unlock()
+		return echoSender(ctx, chunk)
+	}
+
+	result, err := Run[item, item](context.Background(), send, req, &Options{Concurrency: 2})
+	if err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+
+	if len(sizes) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(sizes))
+	}
+	for _, size := range sizes {
+		if size > MaxChunkSize {
+			t.Errorf("Chunk of %d items exceeds the limit", size)
+		}
+	}
+	if maxSeen > 2 {
+		t.Errorf("Expected at most 2 requests in flight, got %d", maxSeen)
+	}
+
+	for i, r := range result.Create {
+		if r.Err != nil || r.Item.ID != i || r.Index != i {
+			t.Fatalf("Unexpected create result %d: %+v", i, r)
+		}
+	}
+	for i, r := range result.Delete {
+		if r.Err != nil || r.Item.ID != 1000+i {
+			t.Fatalf("Unexpected delete result %d: %+v", i, r)
+		}
+	}
+	if err := result.Err(); err != nil {
+		t.Errorf("Expected no item errors, got %v", err)
+	}
+}
+
+func TestRun_ItemErrors(t *testing.T) {
+	send := func(ctx context.Context, chunk *types.BatchRequest[item]) (*types.BatchResponse[json.RawMessage], error) {
+		return &types.BatchResponse[json.RawMessage]{
+			Update: []json.RawMessage{
+				json.RawMessage(`{"id": 1}`),
+				json.RawMessage(`{"id": 0, "error": {"code": "woocommerce_rest_product_invalid_id", "message": "Invalid ID.", "data": {"status": 400}}}`),
+			},
+		}, nil
+	}
+
+	req := &types.BatchRequest[item]{Update: []item{{ID: 1}, {ID: 2}}}
+	result, err := Run[item, item](context.Background(), send, req, nil)
+	if err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+
+	if result.Update[0].Err != nil || result.Update[0].Item.ID != 1 {
+		t.Errorf("Unexpected first result: %+v", result.Update[0])
+	}
+
+	dokanErr, ok := result.Update[1].Err.(*errors.DokanError)
+	if !ok {
+		t.Fatalf("Expected DokanError, got %T", result.Update[1].Err)
+	}
+	if dokanErr.Code != "woocommerce_rest_product_invalid_id" || dokanErr.StatusCode != 400 {
+		t.Errorf("Unexpected error: %+v", dokanErr)
+	}
+
+	failed := result.Failed()
+	if len(failed) != 1 || failed[0].Operation != OperationUpdate || failed[0].Index != 1 {
+		t.Errorf("Unexpected failed items: %+v", failed)
+	}
+	if result.Err() == nil {
+		t.Error("Expected Err() to report the failed item")
+	}
+}
+
+func TestRun_RequestError(t *testing.T) {
+	var calls int32
+	send := func(ctx context.Context, chunk *types.BatchRequest[item]) (*types.BatchResponse[json.RawMessage], error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return nil, fmt.Errorf("connection reset")
+		}
+		return echoSender(ctx, chunk)
+	}
+
+	req := &types.BatchRequest[item]{Create: []item{{ID: 1}, {ID: 2}, {ID: 3}}}
+	result, err := Run[item, item](context.Background(), send, req, &Options{ChunkSize: 2, Concurrency: 1})
+	if err == nil {
+		t.Fatal("Expected Run() to return the request error")
+	}
+
+	if result.Create[0].Err == nil || result.Create[1].Err == nil {
+		t.Error("Expected the items of the failed chunk to carry the error")
+	}
+	if result.Create[2].Err != nil || result.Create[2].Item.ID != 3 {
+		t.Errorf("Expected the second chunk to succeed, got %+v", result.Create[2])
+	}
+}
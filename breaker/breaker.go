@@ -0,0 +1,265 @@
+// Package breaker implements a circuit breaker that stops sending requests
+// to a backend that keeps failing, so that callers fail fast instead of
+// waiting for every request to time out.
+//
+// The breaker starts closed and lets every request through. After too many
+// consecutive network errors or 5xx responses it opens and rejects requests
+// with an errors.CircuitOpenError. Once Config.OpenTimeout has elapsed it
+// becomes half-open and lets a few trial requests through: if they succeed
+// the breaker closes again, otherwise it reopens.
+package breaker
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"time"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/errors"
+)
+
+// Default values used when the Config fields are zero
+const (
+	DefaultNetworkErrorThreshold = 5
+	DefaultServerErrorThreshold  = 5
+	DefaultOpenTimeout           = 30 * time.Second
+	DefaultHalfOpenRequests      = 1
+)
+
+// State is the state of a circuit breaker
+type State int
+
+const (
+	// StateClosed lets every request through
+	StateClosed State = iota
+	// StateOpen rejects every request
+	StateOpen
+	// StateHalfOpen lets a limited number of trial requests through
+	StateHalfOpen
+)
+
+// String returns the name of the state
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Config configures a Breaker
+type Config struct {
+	// NetworkErrorThreshold is the number of consecutive network errors
+	// that opens the circuit. Defaults to DefaultNetworkErrorThreshold.
+	NetworkErrorThreshold int
+
+	// ServerErrorThreshold is the number of consecutive 5xx responses that
+	// opens the circuit. Defaults to DefaultServerErrorThreshold.
+	ServerErrorThreshold int
+
+	// OpenTimeout is how long the circuit stays open before letting trial
+	// requests through. Defaults to DefaultOpenTimeout.
+	OpenTimeout time.Duration
+
+	// HalfOpenRequests is the number of trial requests let through while
+	// half-open. All of them must succeed to close the circuit. Defaults to
+	// DefaultHalfOpenRequests.
+	HalfOpenRequests int
+
+	// OnStateChange, if set, is called after every state change. It is
+	// called synchronously, outside of the breaker lock.
+	OnStateChange func(from, to State)
+}
+
+// Breaker is a circuit breaker. It is safe for concurrent use.
+type Breaker struct {
+	config Config
+
+	mu             sync.Mutex
+	state          State
+	generation     uint64
+	networkErrors  int
+	serverErrors   int
+	openUntil      time.Time
+	trials         int
+	trialSuccesses int
+}
+
+// New creates a closed Breaker from config
+func New(config Config) *Breaker {
+	if config.NetworkErrorThreshold < 1 {
+		config.NetworkErrorThreshold = DefaultNetworkErrorThreshold
+	}
+	if config.ServerErrorThreshold < 1 {
+		config.ServerErrorThreshold = DefaultServerErrorThreshold
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = DefaultOpenTimeout
+	}
+	if config.HalfOpenRequests < 1 {
+		config.HalfOpenRequests = DefaultHalfOpenRequests
+	}
+
+	return &Breaker{config: config}
+}
+
+// Allow reports whether a request may be sent. If it may, the returned done
+// function must be called with the outcome of the request. Otherwise the
+// error is an *errors.CircuitOpenError.
+func (b *Breaker) Allow() (done func(err error), err error) {
+	b.mu.Lock()
+
+	now := time.Now()
+	var changed func()
+	if b.state == StateOpen && !now.Before(b.openUntil) {
+		changed = b.setState(StateHalfOpen, now)
+	}
+
+	switch b.state {
+	case StateOpen:
+		openUntil := b.openUntil
+		b.mu.Unlock()
+		return nil, errors.NewCircuitOpenError(openUntil)
+	case StateHalfOpen:
+		if b.trials >= b.config.HalfOpenRequests {
+			openUntil := b.openUntil
+			b.mu.Unlock()
+			notify(changed)
+			return nil, errors.NewCircuitOpenError(openUntil)
+		}
+		b.trials++
+	}
+
+	generation := b.generation
+	b.mu.Unlock()
+	notify(changed)
+
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() { b.record(generation, err) })
+	}, nil
+}
+
+// record updates the breaker with the outcome of a request admitted in
+// generation. Outcomes of requests admitted before the last state change
+// are ignored, and inconclusive ones only release their trial slot.
+func (b *Breaker) record(generation uint64, err error) {
+	b.mu.Lock()
+
+	if generation != b.generation {
+		b.mu.Unlock()
+		return
+	}
+
+	if inconclusive(err) {
+		if b.state == StateHalfOpen {
+			b.trials--
+		}
+		b.mu.Unlock()
+		return
+	}
+
+	now := time.Now()
+	var changed func()
+	networkErr, serverErr := classify(err)
+	switch b.state {
+	case StateClosed:
+		switch {
+		case networkErr:
+			b.networkErrors++
+		case serverErr:
+			b.serverErrors++
+		default:
+			b.networkErrors, b.serverErrors = 0, 0
+		}
+		if b.networkErrors >= b.config.NetworkErrorThreshold || b.serverErrors >= b.config.ServerErrorThreshold {
+			changed = b.setState(StateOpen, now)
+		}
+	case StateHalfOpen:
+		if networkErr || serverErr {
+			changed = b.setState(StateOpen, now)
+			break
+		}
+		b.trialSuccesses++
+		if b.trialSuccesses >= b.config.HalfOpenRequests {
+			changed = b.setState(StateClosed, now)
+		}
+	}
+
+	b.mu.Unlock()
+	notify(changed)
+}
+
+// setState moves the breaker to state and returns the callback to run once
+// the lock is released, if any. b.mu must be held.
+func (b *Breaker) setState(state State, now time.Time) func() {
+	from := b.state
+	b.state = state
+	b.generation++
+	b.networkErrors, b.serverErrors = 0, 0
+	b.trials, b.trialSuccesses = 0, 0
+	if state == StateOpen {
+		b.openUntil = now.Add(b.config.OpenTimeout)
+	}
+
+	if b.config.OnStateChange == nil {
+		return nil
+	}
+	onStateChange := b.config.OnStateChange
+	return func() { onStateChange(from, state) }
+}
+
+// notify runs a state change callback returned by setState
+func notify(changed func()) {
+	if changed != nil {
+		changed()
+	}
+}
+
+// State returns the current state of the breaker
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && !time.Now().Before(b.openUntil) {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// inconclusive reports whether err proves nothing about the server either
+// way: the request was cancelled by the caller, or it failed with a context
+// error before being sent, such as a timeout waiting for the rate limiter.
+// Errors of requests that were sent are wrapped in an *errors.NetworkError.
+func inconclusive(err error) bool {
+	if stderrors.Is(err, context.Canceled) {
+		return true
+	}
+
+	var netErr *errors.NetworkError
+	return stderrors.Is(err, context.DeadlineExceeded) && !stderrors.As(err, &netErr)
+}
+
+// classify reports whether err is a network error or a 5xx response, the
+// failures that count towards opening the circuit
+func classify(err error) (networkErr, serverErr bool) {
+	if err == nil {
+		return false, false
+	}
+
+	var netErr *errors.NetworkError
+	if stderrors.As(err, &netErr) {
+		return true, false
+	}
+
+	var dokanErr *errors.DokanError
+	if stderrors.As(err, &dokanErr) && dokanErr.StatusCode >= 500 {
+		return false, true
+	}
+	return false, false
+}
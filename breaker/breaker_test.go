@@ -0,0 +1,129 @@
+package breaker
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/errors"
+)
+
+func send(t *testing.T, b *Breaker, err error) {
+	t.Helper()
+	done, allowErr := b.Allow()
+	if allowErr != nil {
+		t.Fatalf("Allow() returned error: %v", allowErr)
+	}
+	done(err)
+}
+
+func TestBreaker_Opens(t *testing.T) {
+	var transitions []string
+	b := New(Config{
+		NetworkErrorThreshold: 2,
+		ServerErrorThreshold:  3,
+		OpenTimeout:           time.Hour,
+		OnStateChange: func(from, to State) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+
+	serverErr := errors.NewDokanError("internal_error", "internal server error", 500)
+	send(t, b, serverErr)
+	send(t, b, serverErr)
+	send(t, b, errors.NewDokanError("bad_request", "bad request", 400))
+	send(t, b, serverErr)
+	send(t, b, serverErr)
+	if b.State() != StateClosed {
+		t.Fatalf("Expected a success to reset the failure count, got %s", b.State())
+	}
+
+	send(t, b, errors.NewNetworkError(context.Canceled))
+	send(t, b, errors.NewNetworkError(context.DeadlineExceeded))
+	send(t, b, errors.NewNetworkError(context.DeadlineExceeded))
+	if b.State() != StateOpen {
+		t.Fatalf("Expected the breaker to open, got %s", b.State())
+	}
+
+	_, err := b.Allow()
+	if !stderrors.Is(err, errors.ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if len(transitions) != 1 || transitions[0] != "closed->open" {
+		t.Errorf("Unexpected transitions: %v", transitions)
+	}
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	var transitions []string
+	b := New(Config{
+		NetworkErrorThreshold: 1,
+		OpenTimeout:           10 * time.Millisecond,
+		HalfOpenRequests:      2,
+		OnStateChange: func(from, to State) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+
+	send(t, b, errors.NewNetworkError(context.DeadlineExceeded))
+	time.Sleep(15 * time.Millisecond)
+
+	// A failed trial reopens the circuit
+	send(t, b, errors.NewDokanError("http_error", "HTTP 503 error", 503))
+	if _, err := b.Allow(); err == nil {
+		t.Fatal("Expected the breaker to reopen")
+	}
+	time.Sleep(15 * time.Millisecond)
+
+	first, err := b.Allow()
+	if err != nil {
+		t.Fatalf("Allow() returned error: %v", err)
+	}
+	second, err := b.Allow()
+	if err != nil {
+		t.Fatalf("Allow() returned error: %v", err)
+	}
+	if _, err := b.Allow(); err == nil {
+		t.Error("Expected only 2 trial requests while half-open")
+	}
+
+	// A cancelled trial frees its slot without counting as a success
+	second(context.Canceled)
+	if b.State() != StateHalfOpen {
+		t.Errorf("Expected a cancelled trial to leave the breaker half-open, got %s", b.State())
+	}
+	third, err := b.Allow()
+	if err != nil {
+		t.Fatalf("Allow() returned error after a cancelled trial: %v", err)
+	}
+
+	// So does a trial that timed out before being sent
+	third(context.DeadlineExceeded)
+	if b.State() != StateHalfOpen {
+		t.Errorf("Expected an unsent trial to leave the breaker half-open, got %s", b.State())
+	}
+	third, err = b.Allow()
+	if err != nil {
+		t.Fatalf("Allow() returned error after an unsent trial: %v", err)
+	}
+	first(nil)
+	if b.State() != StateHalfOpen {
+		t.Errorf("Expected the breaker to stay half-open after 1 success, got %s", b.State())
+	}
+	third(nil)
+
+	if b.State() != StateClosed {
+		t.Errorf("Expected the breaker to close, got %s", b.State())
+	}
+	expected := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if len(transitions) != len(expected) {
+		t.Fatalf("Expected transitions %v, got %v", expected, transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Errorf("Expected transitions %v, got %v", expected, transitions)
+			break
+		}
+	}
+}
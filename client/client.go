@@ -3,22 +3,36 @@ package client
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/diogenes-moreira/dokan-go-sdk/auth"
+	"github.com/diogenes-moreira/dokan-go-sdk/breaker"
+	"github.com/diogenes-moreira/dokan-go-sdk/metrics"
 	"github.com/diogenes-moreira/dokan-go-sdk/products"
 	"github.com/diogenes-moreira/dokan-go-sdk/orders"
+	"github.com/diogenes-moreira/dokan-go-sdk/ratelimit"
 	"github.com/diogenes-moreira/dokan-go-sdk/stores"
 	"github.com/diogenes-moreira/dokan-go-sdk/utils"
 )
 
 // Client is the main Dokan API client
 type Client struct {
-	baseURL    string
-	httpClient utils.HTTPClient
-	auth       auth.Authenticator
-	retryConfig utils.RetryConfig
+	baseURL       string
+	httpClient    utils.HTTPClient
+	authTransport http.RoundTripper
+	authMu        sync.RWMutex
+	auth          auth.Authenticator
+	retryConfig   utils.RetryConfig
+	limiter       *ratelimit.Limiter
+	breaker       *breaker.Breaker
+	logger        *slog.Logger
+	metrics       metrics.Metrics
+	reauth        *reauthenticator
+	do            utils.RequestFunc
 	
 	// Services
 	Products *products.Service
@@ -31,10 +45,53 @@ type Config struct {
 	BaseURL     string
 	Timeout     time.Duration
 	RetryCount  int
+	// UserAgent is sent in the User-Agent header of every request, followed
+	// by the SDK and Go versions
 	UserAgent   string
+	// Debug logs every request and response, with credentials redacted,
+	// through DebugLogger at debug level
 	Debug       bool
+	// DebugLogger receives the debug dumps. Nil logs to standard error.
+	DebugLogger *slog.Logger
+	// Logger, if set, receives one record per request attempt
+	Logger      *slog.Logger
+	// Metrics, if set, receives measurements of every request attempt
+	Metrics     metrics.Metrics
 	Auth        auth.Config
+	// Credentials, if set, supplies the credentials instead of Auth, and
+	// the base URL when BaseURL is empty. See auth.NewProviderAuth.
+	Credentials auth.CredentialProvider
 	HTTPClient  *http.Client
+	// DisableReauth stops the client from refreshing the credentials and
+	// replaying a request once when it is rejected with 401 Unauthorized
+	DisableReauth bool
+	// OnReauth, if set, is called after every refresh of the credentials
+	// triggered by a 401 response, with the error of the refresh, if any
+	OnReauth func(err error)
+	// RetryPolicy decides which failed requests are retried and how long to
+	// wait between attempts. Nil uses utils.DefaultRetryPolicy.
+	RetryPolicy utils.RetryPolicy
+	// RetryBudget, if set, limits the retries of every request made by the
+	// client, and can be shared between clients
+	RetryBudget *utils.RetryBudget
+	// MaxRetryAfter is the longest Retry-After delay the client waits for
+	// before retrying. Requests asked to wait longer fail instead. Zero uses
+	// utils.DefaultMaxRetryAfter.
+	MaxRetryAfter time.Duration
+	// RateLimiter, if set, throttles every request made by the client,
+	// including retries, and can be shared between clients
+	RateLimiter *ratelimit.Limiter
+	// CircuitBreaker, if set, rejects requests with errors.CircuitOpenError
+	// while the site keeps failing, instead of waiting for them to time out
+	CircuitBreaker *breaker.Breaker
+	// Middleware wraps the HTTP client of every attempt. Middleware runs
+	// after authentication, so it sees the request as sent on the wire, and
+	// the first middleware is the outermost one. See ClientBuilder.Use.
+	Middleware []utils.Middleware
+	// RequestMiddleware wraps every call to MakeRequest, around the retry
+	// loop. The first middleware is the outermost one. See
+	// ClientBuilder.UseRequest.
+	RequestMiddleware []utils.RequestMiddleware
 }
 
 // DefaultConfig returns a default configuration
@@ -42,7 +99,7 @@ func DefaultConfig() *Config {
 	return &Config{
 		Timeout:    30 * time.Second,
 		RetryCount: 3,
-		UserAgent:  "dokan-go-sdk/1.0.0",
+		UserAgent:  "dokan-go-sdk/" + Version,
 		Debug:      false,
 	}
 }
@@ -53,23 +110,53 @@ func NewClient(config *Config) (*Client, error) {
 		config = DefaultConfig()
 	}
 	
+	// Create authenticator, from the credential provider if there is one
+	baseURL := config.BaseURL
+	var authenticator auth.Authenticator
+	if config.Credentials != nil {
+		providerAuth, err := auth.NewProviderAuth(context.Background(), config.Credentials, baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create authenticator: %w", err)
+		}
+		if baseURL == "" {
+			baseURL = providerAuth.Config().BaseURL
+		}
+		if config.Logger != nil {
+			logger := config.Logger
+			providerAuth.SetOnReloadError(func(err error) {
+				logger.Warn("dokan credentials reload failed", slog.String("error", err.Error()))
+			})
+		}
+		authenticator = providerAuth
+	}
+	
 	// Validate required fields
-	if config.BaseURL == "" {
+	if baseURL == "" {
 		return nil, fmt.Errorf("base URL is required")
 	}
 	
-	// Create authenticator
-	authenticator, err := auth.NewAuthenticator(config.Auth)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create authenticator: %w", err)
+	if authenticator == nil {
+		authConfig := config.Auth
+		if authConfig.BaseURL == "" {
+			authConfig.BaseURL = baseURL
+		}
+		var err error
+		authenticator, err = auth.NewAuthenticator(authConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create authenticator: %w", err)
+		}
 	}
 	
 	// Create HTTP client if not provided
 	var httpClient utils.HTTPClient
+	var transport http.RoundTripper = http.DefaultTransport
 	if config.HTTPClient != nil {
 		httpClient = config.HTTPClient
+		if config.HTTPClient.Transport != nil {
+			transport = config.HTTPClient.Transport
+		}
 	} else {
-		transport := &http.Transport{
+		transport = &http.Transport{
 			MaxIdleConns:        100,
 			MaxIdleConnsPerHost: 10,
 			IdleConnTimeout:     90 * time.Second,
@@ -81,19 +168,54 @@ func NewClient(config *Config) (*Client, error) {
 		}
 	}
 	
+	// Wrap the HTTP client with middleware, innermost last. Debug dumps
+	// come last to show the request exactly as it is sent.
+	middleware := []utils.Middleware{userAgentMiddleware(userAgent(config.UserAgent))}
+	middleware = append(middleware, config.Middleware...)
+	if config.Debug {
+		logger := config.DebugLogger
+		if logger == nil {
+			logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		}
+		middleware = append(middleware, debugMiddleware(logger))
+	}
+	httpClient = utils.Chain(httpClient, middleware...)
+	
 	// Create retry config
 	retryConfig := utils.RetryConfig{
-		MaxRetries: config.RetryCount,
-		BaseDelay:  1 * time.Second,
-		MaxDelay:   30 * time.Second,
-		Multiplier: 2.0,
+		MaxRetries:    config.RetryCount,
+		BaseDelay:     1 * time.Second,
+		MaxDelay:      30 * time.Second,
+		Multiplier:    2.0,
+		Jitter:        utils.JitterFull,
+		Policy:        config.RetryPolicy,
+		Budget:        config.RetryBudget,
+		MaxRetryAfter: config.MaxRetryAfter,
 	}
 	
 	client := &Client{
-		baseURL:     config.BaseURL,
-		httpClient:  httpClient,
-		auth:        authenticator,
-		retryConfig: retryConfig,
+		baseURL:       baseURL,
+		httpClient:    httpClient,
+		authTransport: middlewareTransport(transport, middleware),
+		auth:          authenticator,
+		retryConfig:   retryConfig,
+		limiter:       config.RateLimiter,
+		breaker:       config.CircuitBreaker,
+		logger:        config.Logger,
+		metrics:       config.Metrics,
+	}
+	client.setUpAuth(authenticator)
+	
+	if !config.DisableReauth {
+		client.reauth = &reauthenticator{
+			auth:      authenticator,
+			onRefresh: config.OnReauth,
+		}
+	}
+	
+	client.do = client.makeRequest
+	for i := len(config.RequestMiddleware) - 1; i >= 0; i-- {
+		client.do = config.RequestMiddleware[i](client.do)
 	}
 	
 	// Initialize services
@@ -104,28 +226,130 @@ func NewClient(config *Config) (*Client, error) {
 	return client, nil
 }
 
-// MakeRequest makes an authenticated HTTP request
+// MakeRequest makes an authenticated HTTP request, retrying failures as
+// allowed by the configured retry policy. Each attempt is logged to the
+// configured logger and goes through the circuit breaker and then the rate
+// limiter, when configured. Attempts they let through are measured by the
+// configured metrics.
+//
+// If the request is rejected with 401 Unauthorized, the credentials are
+// refreshed and the request is replayed once before failing.
 func (c *Client) MakeRequest(ctx context.Context, opts utils.RequestOptions) (*utils.Response, error) {
-	var lastResponse *utils.Response
-	var lastError error
-	
-	err := utils.WithRetry(ctx, c.retryConfig, func() error {
-		// Create a new request for each retry attempt
-		resp, err := utils.MakeRequest(ctx, &authenticatedClient{
-			client: c.httpClient,
-			auth:   c.auth,
-		}, c.baseURL, opts)
-		
-		lastResponse = resp
-		lastError = err
-		return err
+	return c.do(ctx, opts)
+}
+
+// makeRequest implements MakeRequest below the request middleware
+func (c *Client) makeRequest(ctx context.Context, opts utils.RequestOptions) (*utils.Response, error) {
+	if c.reauth == nil {
+		return c.retry(ctx, opts)
+	}
+	
+	generation := c.reauth.current()
+	resp, err := c.retry(ctx, opts)
+	if resp == nil || resp.StatusCode != http.StatusUnauthorized || !c.reauth.refresh(ctx, generation) {
+		return resp, err
+	}
+	return c.retry(ctx, opts)
+}
+
+// retry makes a request, retrying failures as allowed by the retry policy
+func (c *Client) retry(ctx context.Context, opts utils.RequestOptions) (*utils.Response, error) {
+	return utils.DoWithRetry(ctx, c.retryConfig, opts, func(ctx context.Context) (*utils.Response, error) {
+		return c.loggedAttempt(ctx, opts)
 	})
+}
+
+// loggedAttempt makes a single attempt of a request and logs it to the
+// configured logger
+func (c *Client) loggedAttempt(ctx context.Context, opts utils.RequestOptions) (*utils.Response, error) {
+	if c.logger == nil {
+		return c.attempt(ctx, opts)
+	}
+	
+	start := time.Now()
+	resp, err := c.attempt(ctx, opts)
+	logAttempt(ctx, c.logger, opts, resp, err, time.Since(start))
+	return resp, err
+}
+
+// attempt makes a single attempt of a request once the circuit breaker
+// allows it
+func (c *Client) attempt(ctx context.Context, opts utils.RequestOptions) (*utils.Response, error) {
+	if c.breaker == nil {
+		return c.throttledSend(ctx, opts)
+	}
+	
+	done, err := c.breaker.Allow()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.throttledSend(ctx, opts)
+	done(err)
+	return resp, err
+}
+
+// throttledSend makes a single attempt of a request once the rate limiter
+// admits it
+func (c *Client) throttledSend(ctx context.Context, opts utils.RequestOptions) (*utils.Response, error) {
+	if c.limiter == nil {
+		return c.measuredSend(ctx, opts)
+	}
 	
+	release, err := c.limiter.Acquire(ctx)
 	if err != nil {
-		return lastResponse, err
+		return nil, err
+	}
+	resp, err := c.measuredSend(ctx, opts)
+	release(err)
+	return resp, err
+}
+
+// measuredSend makes a single attempt of a request and reports it to the
+// configured metrics, so that they only count attempts actually sent and
+// their duration excludes the wait for the rate limiter
+func (c *Client) measuredSend(ctx context.Context, opts utils.RequestOptions) (*utils.Response, error) {
+	if c.metrics == nil {
+		return c.send(ctx, opts)
 	}
 	
-	return lastResponse, lastError
+	attempt, _ := utils.AttemptFromContext(ctx)
+	return metrics.Observe(c.metrics, opts, attempt, func() (*utils.Response, error) {
+		return c.send(ctx, opts)
+	})
+}
+
+// send makes a single authenticated attempt of a request
+func (c *Client) send(ctx context.Context, opts utils.RequestOptions) (*utils.Response, error) {
+	return utils.MakeRequest(ctx, &authenticatedClient{
+		client: c.httpClient,
+		auth:   c.GetAuth(),
+	}, c.baseURL, opts)
+}
+
+// setUpAuth gives authenticators that log in the HTTP client and transport
+// of the client, so that their requests are sent like the client's
+func (c *Client) setUpAuth(authenticator auth.Authenticator) {
+	if a, ok := authenticator.(auth.HTTPAuthenticator); ok {
+		a.SetHTTPClient(c.httpClient)
+	}
+	if a, ok := authenticator.(auth.TransportAuthenticator); ok {
+		a.SetTransport(c.authTransport)
+	}
+}
+
+// middlewareTransport returns a transport that sends requests through
+// middleware and then transport, for authenticators that need an HTTP client
+// of their own
+func middlewareTransport(transport http.RoundTripper, middleware []utils.Middleware) http.RoundTripper {
+	return roundTripperFunc(utils.Chain(utils.HTTPClientFunc(transport.RoundTrip), middleware...).Do)
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req)
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
 }
 
 // authenticatedClient wraps an HTTP client with authentication
@@ -141,7 +365,13 @@ func (ac *authenticatedClient) Do(req *http.Request) (*http.Response, error) {
 		return nil, fmt.Errorf("authentication failed: %w", err)
 	}
 	
-	return ac.client.Do(req)
+	resp, err := ac.client.Do(req)
+	if err == nil {
+		if observer, ok := ac.auth.(auth.ResponseObserver); ok {
+			observer.ObserveResponse(resp)
+		}
+	}
+	return resp, err
 }
 
 // ClientBuilder provides a fluent interface for building clients
@@ -174,6 +404,99 @@ func (b *ClientBuilder) RetryCount(count int) *ClientBuilder {
 	return b
 }
 
+// RetryPolicy sets the policy that decides which failed requests are retried
+func (b *ClientBuilder) RetryPolicy(policy utils.RetryPolicy) *ClientBuilder {
+	b.config.RetryPolicy = policy
+	return b
+}
+
+// RetryBudget sets a budget that limits retries across concurrent requests
+func (b *ClientBuilder) RetryBudget(budget *utils.RetryBudget) *ClientBuilder {
+	b.config.RetryBudget = budget
+	return b
+}
+
+// MaxRetryAfter sets the longest Retry-After delay waited for before
+// retrying
+func (b *ClientBuilder) MaxRetryAfter(delay time.Duration) *ClientBuilder {
+	b.config.MaxRetryAfter = delay
+	return b
+}
+
+// RateLimit throttles requests with a new limiter built from config
+func (b *ClientBuilder) RateLimit(config ratelimit.Config) *ClientBuilder {
+	b.config.RateLimiter = ratelimit.New(config)
+	return b
+}
+
+// RateLimiter throttles requests with limiter, which may be shared with
+// other clients talking to the same site
+func (b *ClientBuilder) RateLimiter(limiter *ratelimit.Limiter) *ClientBuilder {
+	b.config.RateLimiter = limiter
+	return b
+}
+
+// CircuitBreaker fails requests fast with a new breaker built from config
+// while the site keeps failing
+func (b *ClientBuilder) CircuitBreaker(config breaker.Config) *ClientBuilder {
+	b.config.CircuitBreaker = breaker.New(config)
+	return b
+}
+
+// Use appends middleware to the chain that wraps every HTTP request.
+//
+// Requests go through the retry loop, the circuit breaker and the rate
+// limiter first, are then authenticated, and finally pass through the
+// middleware in the order it was added before reaching the HTTP client.
+// Each retry attempt goes through the whole middleware chain again.
+func (b *ClientBuilder) Use(middleware ...utils.Middleware) *ClientBuilder {
+	b.config.Middleware = append(b.config.Middleware, middleware...)
+	return b
+}
+
+// Logger sets the logger that receives one structured record per request
+// attempt, with its method, path, status, duration, attempt number, retry
+// reason and error kind. Successful attempts are logged at info level,
+// client errors and rate limiting at warn level and other failures at error
+// level.
+func (b *ClientBuilder) Logger(logger *slog.Logger) *ClientBuilder {
+	b.config.Logger = logger
+	return b
+}
+
+// Metrics sets the collector that receives measurements of every request
+// attempt: its duration and status, retries, rate limiting and the number of
+// attempts in flight. See the metrics/prometheus module for a Prometheus
+// collector.
+func (b *ClientBuilder) Metrics(m metrics.Metrics) *ClientBuilder {
+	b.config.Metrics = m
+	return b
+}
+
+// Reauth enables or disables refreshing the credentials and replaying a
+// request once when it is rejected with 401 Unauthorized. It is enabled by
+// default.
+func (b *ClientBuilder) Reauth(enabled bool) *ClientBuilder {
+	b.config.DisableReauth = !enabled
+	return b
+}
+
+// OnReauth sets a function that is called after every refresh of the
+// credentials triggered by a 401 response, with the error of the refresh,
+// for instance to notify about credential rotation
+func (b *ClientBuilder) OnReauth(fn func(err error)) *ClientBuilder {
+	b.config.OnReauth = fn
+	return b
+}
+
+// UseRequest appends middleware that wraps every call to MakeRequest. It
+// runs once per call, around the retry loop, so it sees the final outcome
+// of a request, while middleware added with Use runs once per attempt.
+func (b *ClientBuilder) UseRequest(middleware ...utils.RequestMiddleware) *ClientBuilder {
+	b.config.RequestMiddleware = append(b.config.RequestMiddleware, middleware...)
+	return b
+}
+
 // UserAgent sets the user agent string
 func (b *ClientBuilder) UserAgent(userAgent string) *ClientBuilder {
 	b.config.UserAgent = userAgent
@@ -186,6 +509,13 @@ func (b *ClientBuilder) Debug(debug bool) *ClientBuilder {
 	return b
 }
 
+// DebugLogger sets the logger that receives debug dumps. Records are logged
+// at debug level, so the logger must have it enabled.
+func (b *ClientBuilder) DebugLogger(logger *slog.Logger) *ClientBuilder {
+	b.config.DebugLogger = logger
+	return b
+}
+
 // BasicAuth configures HTTP Basic Authentication
 func (b *ClientBuilder) BasicAuth(username, password string) *ClientBuilder {
 	b.config.Auth = auth.Config{
@@ -205,6 +535,63 @@ func (b *ClientBuilder) JWTAuth(token string) *ClientBuilder {
 	return b
 }
 
+// Credentials takes the credentials, and the base URL if not set, from
+// provider, which is checked periodically for rotated credentials:
+//
+//	NewClientBuilder().Credentials(auth.NewChainProvider(
+//		auth.NewEnvProvider(),
+//		auth.NewSecretDirProvider("/run/secrets/dokan"),
+//		auth.NewFileProvider("", ""),
+//	))
+func (b *ClientBuilder) Credentials(provider auth.CredentialProvider) *ClientBuilder {
+	b.config.Credentials = provider
+	return b
+}
+
+// JWTLogin configures JWT Authentication with tokens obtained from the JWT
+// Auth plugin by logging in with username and password
+func (b *ClientBuilder) JWTLogin(username, password string) *ClientBuilder {
+	b.config.Auth = auth.Config{
+		Type:     auth.AuthTypeJWT,
+		Username: username,
+		Password: password,
+	}
+	return b
+}
+
+// ApplicationPassword configures authentication with a WordPress
+// Application Password, which lets a vendor act with their own account
+func (b *ClientBuilder) ApplicationPassword(username, password string) *ClientBuilder {
+	b.config.Auth = auth.Config{
+		Type:     auth.AuthTypeApplicationPassword,
+		Username: username,
+		Password: password,
+	}
+	return b
+}
+
+// CookieAuth configures WordPress cookie authentication, logging in through
+// wp-login.php with username and password
+func (b *ClientBuilder) CookieAuth(username, password string) *ClientBuilder {
+	b.config.Auth = auth.Config{
+		Type:     auth.AuthTypeCookie,
+		Username: username,
+		Password: password,
+	}
+	return b
+}
+
+// WooCommerceKeys configures authentication with WooCommerce REST API keys:
+// Basic Authentication over HTTPS and OAuth 1.0a signatures over HTTP
+func (b *ClientBuilder) WooCommerceKeys(consumerKey, consumerSecret string) *ClientBuilder {
+	b.config.Auth = auth.Config{
+		Type:           auth.AuthTypeWooCommerceKeys,
+		ConsumerKey:    consumerKey,
+		ConsumerSecret: consumerSecret,
+	}
+	return b
+}
+
 // HTTPClient sets a custom HTTP client
 func (b *ClientBuilder) HTTPClient(client *http.Client) *ClientBuilder {
 	b.config.HTTPClient = client
@@ -223,11 +610,50 @@ func (c *Client) GetBaseURL() string {
 
 // GetAuth returns the authenticator
 func (c *Client) GetAuth() auth.Authenticator {
+	c.authMu.RLock()
+	defer c.authMu.RUnlock()
 	return c.auth
 }
 
-// SetAuth sets a new authenticator
+// ValidateAuth checks the credentials against the server, for
+// authenticators that implement auth.Validator, such as application
+// passwords and cookie authentication. Other authenticators are not checked.
+func (c *Client) ValidateAuth(ctx context.Context) error {
+	if validator, ok := c.GetAuth().(auth.Validator); ok {
+		return validator.Validate(ctx)
+	}
+	return nil
+}
+
+// GetMetrics returns the metrics collector, or nil if requests are not
+// measured
+func (c *Client) GetMetrics() metrics.Metrics {
+	return c.metrics
+}
+
+// GetRateLimiter returns the rate limiter, or nil if requests are not
+// throttled. Its Stats report the time spent waiting.
+func (c *Client) GetRateLimiter() *ratelimit.Limiter {
+	return c.limiter
+}
+
+// GetCircuitBreaker returns the circuit breaker, or nil if there is none
+func (c *Client) GetCircuitBreaker() *breaker.Breaker {
+	return c.breaker
+}
+
+// SetAuth sets a new authenticator, set up like the one the client was
+// created with. Requests rejected with 401 Unauthorized from then on refresh
+// the new authenticator.
 func (c *Client) SetAuth(authenticator auth.Authenticator) {
+	c.setUpAuth(authenticator)
+	
+	c.authMu.Lock()
 	c.auth = authenticator
+	c.authMu.Unlock()
+	
+	if c.reauth != nil {
+		c.reauth.setAuth(authenticator)
+	}
 }
 
@@ -2,13 +2,23 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	stderrors "errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/diogenes-moreira/dokan-go-sdk/auth"
+	"github.com/diogenes-moreira/dokan-go-sdk/breaker"
 	"github.com/diogenes-moreira/dokan-go-sdk/errors"
+	"github.com/diogenes-moreira/dokan-go-sdk/metrics"
+	"github.com/diogenes-moreira/dokan-go-sdk/ratelimit"
 	"github.com/diogenes-moreira/dokan-go-sdk/utils"
 )
 
@@ -226,3 +236,535 @@ func TestClient_MakeRequest_Unauthorized(t *testing.T) {
 	}
 }
 
+
+func TestClient_MakeRequest_CircuitBreaker(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	
+	client, err := NewClientBuilder().
+		BaseURL(server.URL).
+		BasicAuth("testuser", "testpass").
+		RetryCount(0).
+		CircuitBreaker(breaker.Config{ServerErrorThreshold: 2, OpenTimeout: time.Hour}).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	
+	opts := utils.RequestOptions{
+		Method: http.MethodGet,
+		Path:   "/test",
+	}
+	for i := 0; i < 4; i++ {
+		client.MakeRequest(context.Background(), opts)
+	}
+	
+	_, err = client.MakeRequest(context.Background(), opts)
+	if !stderrors.Is(err, errors.ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected requests to stop reaching the server after 2 failures, got %d", calls)
+	}
+}
+
+func TestClient_MakeRequest_CircuitBreakerRateLimitTimeout(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	
+	client, err := NewClientBuilder().
+		BaseURL(server.URL).
+		BasicAuth("testuser", "testpass").
+		RetryCount(0).
+		CircuitBreaker(breaker.Config{NetworkErrorThreshold: 1, OpenTimeout: 10 * time.Millisecond, HalfOpenRequests: 1}).
+		RateLimit(ratelimit.Config{RequestsPerSecond: 0.001, Burst: 1}).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	
+	// Open the circuit and use up the rate limiter's only token
+	done, _ := client.GetCircuitBreaker().Allow()
+	done(errors.NewNetworkError(context.DeadlineExceeded))
+	release, err := client.GetRateLimiter().Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire() returned error: %v", err)
+	}
+	release(nil)
+	time.Sleep(15 * time.Millisecond)
+	
+	// The half-open trial times out waiting for the rate limiter
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.MakeRequest(ctx, utils.RequestOptions{Method: http.MethodGet, Path: "/test"})
+	if !stderrors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected a rate limiter timeout, got %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("Expected no request to reach the server, got %d", calls)
+	}
+	
+	// The trial proved nothing, so the circuit stays half-open and admits
+	// another one
+	if state := client.GetCircuitBreaker().State(); state != breaker.StateHalfOpen {
+		t.Errorf("Expected the breaker to stay half-open, got %s", state)
+	}
+	if _, err := client.GetCircuitBreaker().Allow(); err != nil {
+		t.Errorf("Expected the trial slot to be released, got %v", err)
+	}
+}
+
+func TestClientBuilder_Use(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Request-Source") != "worker" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	
+	var sawAuth bool
+	injectHeader := func(next utils.HTTPClient) utils.HTTPClient {
+		return utils.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+			sawAuth = req.Header.Get("Authorization") != ""
+			req.Header.Set("X-Request-Source", "worker")
+			return next.Do(req)
+		})
+	}
+	
+	client, err := NewClientBuilder().
+		BaseURL(server.URL).
+		BasicAuth("testuser", "testpass").
+		Use(injectHeader).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	
+	_, err = client.MakeRequest(context.Background(), utils.RequestOptions{
+		Method: http.MethodGet,
+		Path:   "/test",
+	})
+	if err != nil {
+		t.Fatalf("MakeRequest() returned error: %v", err)
+	}
+	if !sawAuth {
+		t.Error("Expected middleware to run after authentication")
+	}
+}
+
+// recordingMetrics records the measurements reported by a client
+type recordingMetrics struct {
+	started     int
+	finished    []string
+	retries     []string
+	rateLimited int
+}
+
+func (m *recordingMetrics) RequestStarted(labels metrics.Labels) {
+	m.started++
+}
+
+func (m *recordingMetrics) RequestFinished(labels metrics.Labels, status string, duration time.Duration) {
+	m.finished = append(m.finished, labels.Service+" "+labels.Operation+" "+status)
+}
+
+func (m *recordingMetrics) RequestRetried(labels metrics.Labels, reason string) {
+	m.retries = append(m.retries, reason)
+}
+
+func (m *recordingMetrics) RateLimited(labels metrics.Labels) {
+	m.rateLimited++
+}
+
+func TestClientBuilder_Metrics(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	
+	recorder := &recordingMetrics{}
+	client, err := NewClientBuilder().
+		BaseURL(server.URL).
+		BasicAuth("testuser", "testpass").
+		RetryPolicy(utils.DefaultRetryPolicy{BaseDelay: time.Millisecond}).
+		Metrics(recorder).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	
+	_, err = client.MakeRequest(context.Background(), utils.RequestOptions{
+		Method:    http.MethodGet,
+		Path:      "/products/42",
+		Operation: "products.get",
+	})
+	if err != nil {
+		t.Fatalf("MakeRequest() returned error: %v", err)
+	}
+	
+	expected := []string{"products products.get 429", "products products.get 200"}
+	if recorder.started != 2 || len(recorder.finished) != 2 || recorder.finished[0] != expected[0] || recorder.finished[1] != expected[1] {
+		t.Errorf("Expected 2 attempts %v, got %d started and %v", expected, recorder.started, recorder.finished)
+	}
+	if len(recorder.retries) != 1 || recorder.retries[0] != errors.KindRateLimit {
+		t.Errorf("Expected 1 retry because of rate limiting, got %v", recorder.retries)
+	}
+	if recorder.rateLimited != 1 {
+		t.Errorf("Expected 1 rate limited attempt, got %d", recorder.rateLimited)
+	}
+	
+	// Attempts rejected by an open circuit breaker are not measured
+	recorder = &recordingMetrics{}
+	client, err = NewClientBuilder().
+		BaseURL(server.URL).
+		BasicAuth("testuser", "testpass").
+		CircuitBreaker(breaker.Config{NetworkErrorThreshold: 1}).
+		Metrics(recorder).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	done, _ := client.GetCircuitBreaker().Allow()
+	done(errors.NewNetworkError(context.DeadlineExceeded))
+	
+	if _, err := client.MakeRequest(context.Background(), utils.RequestOptions{Method: http.MethodGet, Path: "/products/42"}); err == nil {
+		t.Fatal("Expected the open circuit breaker to reject the request")
+	}
+	if recorder.started != 0 || len(recorder.finished) != 0 {
+		t.Errorf("Expected no measurements, got %d started and %v", recorder.started, recorder.finished)
+	}
+}
+
+func TestClientBuilder_WooCommerceKeys(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	
+	client, err := NewClientBuilder().
+		BaseURL(server.URL).
+		WooCommerceKeys("ck_123", "cs_456").
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	
+	_, err = client.MakeRequest(context.Background(), utils.RequestOptions{
+		Method: http.MethodGet,
+		Path:   "/wp-json/dokan/v1/products",
+		Query:  struct{ Page int `url:"page"` }{Page: 2},
+	})
+	if err != nil {
+		t.Fatalf("MakeRequest() returned error: %v", err)
+	}
+	
+	// The test server speaks plain HTTP, so requests are signed
+	if query.Get("oauth_signature") == "" || query.Get("oauth_consumer_key") != "ck_123" || query.Get("page") != "2" {
+		t.Errorf("Expected a signed request, got %v", query)
+	}
+	if query.Get("consumer_secret") != "" {
+		t.Error("Expected the consumer secret not to be sent over HTTP")
+	}
+}
+
+func TestClient_MakeRequest_Reauth(t *testing.T) {
+	logins, calls := 0, 0
+	accepted := "token-2"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == auth.JWTTokenPath {
+			logins++
+			fmt.Fprintf(w, `{"token": "token-%d"}`, logins)
+			return
+		}
+		calls++
+		if r.Header.Get("Authorization") != "Bearer "+accepted {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	
+	var notified []error
+	client, err := NewClientBuilder().
+		BaseURL(server.URL).
+		JWTLogin("vendor", "secret").
+		OnReauth(func(err error) {
+			notified = append(notified, err)
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	
+	// The first token is revoked, so the request is replayed with a new one
+	opts := utils.RequestOptions{
+		Method: http.MethodGet,
+		Path:   "/test",
+	}
+	if _, err := client.MakeRequest(context.Background(), opts); err != nil {
+		t.Fatalf("MakeRequest() returned error: %v", err)
+	}
+	if calls != 2 || logins != 2 || len(notified) != 1 || notified[0] != nil {
+		t.Errorf("Expected 1 refresh and 1 replay, got %d calls, %d logins and %v", calls, logins, notified)
+	}
+	
+	// Credentials rejected right after a refresh are not refreshed again
+	accepted = "none"
+	calls = 0
+	_, err = client.MakeRequest(context.Background(), opts)
+	var authErr *errors.AuthenticationError
+	if !stderrors.As(err, &authErr) {
+		t.Errorf("Expected AuthenticationError, got %v", err)
+	}
+	if calls != 1 || len(notified) != 1 {
+		t.Errorf("Expected no refresh loop, got %d calls and %d refreshes", calls, len(notified))
+	}
+}
+
+func TestClient_MakeRequest_ReauthNotRefreshable(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	
+	var notified []error
+	client, err := NewClientBuilder().
+		BaseURL(server.URL).
+		JWTAuth("token").
+		OnReauth(func(err error) {
+			notified = append(notified, err)
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	
+	// A token without a way to refresh it is not refreshed
+	if _, err := client.MakeRequest(context.Background(), utils.RequestOptions{Method: http.MethodGet, Path: "/test"}); err == nil {
+		t.Fatal("Expected the request to fail")
+	}
+	if calls != 1 || len(notified) != 0 {
+		t.Errorf("Expected no refresh, got %d calls and %v", calls, notified)
+	}
+}
+
+func TestClient_MakeRequest_ReauthRotatedCredentials(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if _, password, _ := r.BasicAuth(); password != "new" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "username"), []byte("admin"), 0o600)
+	os.WriteFile(filepath.Join(dir, "password"), []byte("old"), 0o600)
+	client, err := NewClientBuilder().
+		BaseURL(server.URL).
+		Credentials(auth.NewSecretDirProvider(dir)).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	
+	// The secret is rotated before the next periodic check, so the 401 reads
+	// it again and the request is replayed
+	os.WriteFile(filepath.Join(dir, "password"), []byte("new"), 0o600)
+	if _, err := client.MakeRequest(context.Background(), utils.RequestOptions{Method: http.MethodGet, Path: "/test"}); err != nil {
+		t.Fatalf("MakeRequest() returned error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 1 replay with the rotated password, got %d calls", calls)
+	}
+}
+
+func TestClient_SetAuth(t *testing.T) {
+	var logins []string
+	accepted := "vendor-2"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == auth.JWTTokenPath {
+			var body struct{ Username string }
+			json.NewDecoder(r.Body).Decode(&body)
+			logins = append(logins, body.Username+" "+r.Header.Get("X-Middleware"))
+			fmt.Fprintf(w, `{"token": "%s-%d"}`, body.Username, len(logins))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+accepted {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	
+	middleware := func(next utils.HTTPClient) utils.HTTPClient {
+		return utils.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Middleware", "yes")
+			return next.Do(req)
+		})
+	}
+	client, err := NewClientBuilder().
+		BaseURL(server.URL).
+		JWTLogin("admin", "secret").
+		Use(middleware).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	
+	// The first token of the new authenticator is revoked, so it is the one
+	// refreshed before the request is replayed
+	client.SetAuth(auth.NewJWTLoginAuth(server.URL, "vendor", "secret"))
+	if _, err := client.MakeRequest(context.Background(), utils.RequestOptions{Method: http.MethodGet, Path: "/test"}); err != nil {
+		t.Fatalf("MakeRequest() returned error: %v", err)
+	}
+	expected := []string{"vendor yes", "vendor yes"}
+	if len(logins) != len(expected) || logins[0] != expected[0] || logins[1] != expected[1] {
+		t.Errorf("Expected the new authenticator to log in twice through the middleware, got %v", logins)
+	}
+}
+
+// blockingAuth is a refreshable authenticator whose refresh lasts until its
+// context is done
+type blockingAuth struct {
+	auth.BasicAuth
+	started chan struct{}
+}
+
+func (a *blockingAuth) CanRefresh() bool { return true }
+
+func (a *blockingAuth) RefreshContext(ctx context.Context) error {
+	close(a.started)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestReauthenticator_WaitHonoursContext(t *testing.T) {
+	var notified []error
+	authenticator := &blockingAuth{started: make(chan struct{})}
+	r := &reauthenticator{auth: authenticator, onRefresh: func(err error) { notified = append(notified, err) }}
+	
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	result := make(chan bool)
+	go func() { result <- r.refresh(leaderCtx, 0) }()
+	<-authenticator.started
+	
+	// A request waiting for the refresh gives up with its own deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if r.refresh(ctx, 0) {
+		t.Error("Expected the waiting request not to be replayed")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the wait to end with the context, took %v", elapsed)
+	}
+	
+	// An abandoned refresh is neither reported nor rate limited
+	cancelLeader()
+	if <-result {
+		t.Error("Expected the abandoned refresh to fail")
+	}
+	if len(notified) != 0 || !r.last.IsZero() {
+		t.Errorf("Expected the abandoned refresh to be ignored, got %v and %v", notified, r.last)
+	}
+}
+
+// staticProvider supplies fixed credentials
+type staticProvider auth.Config
+
+func (p staticProvider) Credentials(ctx context.Context) (auth.Config, error) {
+	return auth.Config(p), nil
+}
+
+func TestClientBuilder_CookieAuth(t *testing.T) {
+	var loginAgents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case auth.WPLoginPath:
+			loginAgents = append(loginAgents, r.Header.Get("User-Agent")+" "+r.Header.Get("X-Middleware"))
+			http.SetCookie(w, &http.Cookie{Name: "wordpress_logged_in_abc", Value: "session", Path: "/"})
+			http.Redirect(w, r, "/wp-admin/", http.StatusFound)
+		case "/wp-admin/admin-ajax.php":
+			loginAgents = append(loginAgents, r.Header.Get("User-Agent")+" "+r.Header.Get("X-Middleware"))
+			w.Write([]byte("nonce123"))
+		case auth.WPUsersMePath:
+			sessions := 0
+			for _, cookie := range r.Cookies() {
+				if cookie.Name == "wordpress_logged_in_abc" {
+					sessions++
+				}
+			}
+			if sessions != 1 || r.Header.Get("X-WP-Nonce") != "nonce123" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(fmt.Sprintf(`{"code": "bad_session", "message": "%d session cookies"}`, sessions)))
+				return
+			}
+			w.Write([]byte(`{"id": 7}`))
+		}
+	}))
+	defer server.Close()
+	
+	middleware := func(next utils.HTTPClient) utils.HTTPClient {
+		return utils.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Middleware", "yes")
+			return next.Do(req)
+		})
+	}
+	
+	builders := map[string]*ClientBuilder{
+		"CookieAuth": NewClientBuilder().BaseURL(server.URL).CookieAuth("vendor", "secret"),
+		"Credentials": NewClientBuilder().Credentials(staticProvider{
+			Type:     auth.AuthTypeCookie,
+			BaseURL:  server.URL,
+			Username: "vendor",
+			Password: "secret",
+		}),
+	}
+	for name, builder := range builders {
+		t.Run(name, func(t *testing.T) {
+			loginAgents = nil
+			client, err := builder.UserAgent("my-app/1.0").Use(middleware).Build()
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			
+			if err := client.ValidateAuth(context.Background()); err != nil {
+				t.Fatalf("ValidateAuth() returned error: %v", err)
+			}
+			if len(loginAgents) != 2 {
+				t.Fatalf("Expected a login and a nonce request, got %v", loginAgents)
+			}
+			for _, agent := range loginAgents {
+				if !strings.HasPrefix(agent, "my-app/1.0 ") || !strings.HasSuffix(agent, " yes") {
+					t.Errorf("Expected login requests to go through the client middleware, got %q", agent)
+				}
+			}
+		})
+	}
+}
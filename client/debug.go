@@ -0,0 +1,201 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
+)
+
+// Version is the version of the SDK reported in the User-Agent header
+const Version = "1.0.0"
+
+// maxDumpBody is the number of body bytes included in debug dumps, once
+// redacted
+const maxDumpBody = 8 << 10
+
+// redacted replaces sensitive values in debug dumps
+const redacted = "[REDACTED]"
+
+// sdkUserAgent identifies the SDK and the Go version in the User-Agent header
+func sdkUserAgent() string {
+	return "dokan-go-sdk/" + Version + " go/" + strings.TrimPrefix(runtime.Version(), "go")
+}
+
+// userAgent returns the User-Agent header for a configured product string,
+// suffixed with the SDK and Go versions
+func userAgent(product string) string {
+	product = strings.TrimSpace(product)
+	if product == "" || product == "dokan-go-sdk/"+Version {
+		return sdkUserAgent()
+	}
+	return product + " " + sdkUserAgent()
+}
+
+// userAgentMiddleware sets the User-Agent header of requests that do not
+// already have one
+func userAgentMiddleware(userAgent string) utils.Middleware {
+	return func(next utils.HTTPClient) utils.HTTPClient {
+		return utils.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("User-Agent") == "" {
+				req.Header.Set("User-Agent", userAgent)
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+// debugMiddleware logs every request and response at debug level, with
+// credentials redacted
+func debugMiddleware(logger *slog.Logger) utils.Middleware {
+	return func(next utils.HTTPClient) utils.HTTPClient {
+		return utils.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+
+			var reqBody []byte
+			if req.GetBody != nil {
+				if body, err := req.GetBody(); err == nil {
+					reqBody, _ = io.ReadAll(body)
+					body.Close()
+				}
+			}
+
+			logger.DebugContext(ctx, "dokan request",
+				slog.String("method", req.Method),
+				slog.String("url", redactURL(req.URL)),
+				slog.Any("headers", redactHeader(req.Header)),
+				slog.String("body", redactBody(req.Header.Get("Content-Type"), reqBody)),
+			)
+
+			resp, err := next.Do(req)
+			if err != nil {
+				logger.DebugContext(ctx, "dokan request failed",
+					slog.String("method", req.Method),
+					slog.String("url", redactURL(req.URL)),
+					slog.String("error", err.Error()),
+				)
+				return resp, err
+			}
+
+			// Buffer the body so that it can still be read by the caller
+			respBody, readErr := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(respBody))
+			if readErr != nil {
+				return resp, readErr
+			}
+
+			logger.DebugContext(ctx, "dokan response",
+				slog.String("method", req.Method),
+				slog.String("url", redactURL(req.URL)),
+				slog.Int("status", resp.StatusCode),
+				slog.Any("headers", redactHeader(resp.Header)),
+				slog.String("body", redactBody(resp.Header.Get("Content-Type"), respBody)),
+			)
+
+			return resp, nil
+		})
+	}
+}
+
+// isSensitive reports whether a header, query parameter or JSON field with
+// the given name holds credentials
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, marker := range []string{"authorization", "cookie", "password", "passwd", "pwd", "token", "secret", "signature", "nonce", "consumer_key"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeader returns a copy of header with credentials redacted
+func redactHeader(header http.Header) http.Header {
+	clone := header.Clone()
+	for name := range clone {
+		if isSensitive(name) {
+			clone[name] = []string{redacted}
+		}
+	}
+	return clone
+}
+
+// redactURL returns u as a string with credentials in the user info and
+// the query string redacted
+func redactURL(u *url.URL) string {
+	clone := *u
+	if clone.User != nil {
+		clone.User = url.User(redacted)
+	}
+	clone.RawQuery = redactValues(clone.Query()).Encode()
+	return clone.String()
+}
+
+// redactValues redacts the sensitive entries of values in place
+func redactValues(values url.Values) url.Values {
+	for name := range values {
+		if isSensitive(name) {
+			values[name] = []string{redacted}
+		}
+	}
+	return values
+}
+
+// redactBody returns body as a string with credentials redacted from JSON
+// and form-encoded content, truncated to maxDumpBody bytes. Bodies that
+// cannot be parsed, and so cannot be redacted, are omitted.
+func redactBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var dump string
+	var value interface{}
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return fmt.Sprintf("<%d bytes omitted>", len(body))
+		}
+		dump = redactValues(values).Encode()
+	} else if json.Unmarshal(body, &value) == nil {
+		redactedBody, err := json.Marshal(redactJSON(value))
+		if err != nil {
+			return fmt.Sprintf("<%d bytes omitted>", len(body))
+		}
+		dump = string(redactedBody)
+	} else {
+		return fmt.Sprintf("<%d bytes omitted>", len(body))
+	}
+
+	if len(dump) > maxDumpBody {
+		return dump[:maxDumpBody] + "... (truncated)"
+	}
+	return dump
+}
+
+// redactJSON redacts the sensitive fields of a decoded JSON value
+func redactJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitive(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactJSON(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSON(item)
+		}
+	}
+	return value
+}
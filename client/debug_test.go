@@ -0,0 +1,135 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
+)
+
+func TestUserAgent(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewClientBuilder().
+		BaseURL(server.URL).
+		BasicAuth("testuser", "testpass").
+		UserAgent("inventory-sync/2.1").
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, err := client.MakeRequest(context.Background(), utils.RequestOptions{Method: http.MethodGet, Path: "/test"}); err != nil {
+		t.Fatalf("MakeRequest() returned error: %v", err)
+	}
+
+	if !strings.HasPrefix(received, "inventory-sync/2.1 dokan-go-sdk/"+Version+" go/") {
+		t.Errorf("Unexpected User-Agent: %q", received)
+	}
+
+	if ua := userAgent(DefaultConfig().UserAgent); !strings.HasPrefix(ua, "dokan-go-sdk/"+Version+" go/") {
+		t.Errorf("Unexpected default User-Agent: %q", ua)
+	}
+}
+
+func TestDebug_Redacts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"token": "eyJ.secret.jwt", "user_display_name": "vendor"}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client, err := NewClientBuilder().
+		BaseURL(server.URL).
+		BasicAuth("testuser", "hunter2").
+		Debug(true).
+		DebugLogger(logger).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp, err := client.MakeRequest(context.Background(), utils.RequestOptions{
+		Method: http.MethodPost,
+		Path:   "/login",
+		Body:   map[string]string{"username": "testuser", "password": "hunter2"},
+	})
+	if err != nil {
+		t.Fatalf("MakeRequest() returned error: %v", err)
+	}
+	if !strings.Contains(string(resp.Body), "eyJ.secret.jwt") {
+		t.Errorf("Expected the response body to be left intact, got %s", resp.Body)
+	}
+
+	dump := output.String()
+	for _, secret := range []string{"hunter2", "eyJ.secret.jwt", "Basic "} {
+		if strings.Contains(dump, secret) {
+			t.Errorf("Debug output leaks %q:\n%s", secret, dump)
+		}
+	}
+	for _, expected := range []string{"dokan request", "dokan response", "vendor", redacted} {
+		if !strings.Contains(dump, expected) {
+			t.Errorf("Expected debug output to contain %q:\n%s", expected, dump)
+		}
+	}
+}
+
+func TestDebug_RedactsLargeBodies(t *testing.T) {
+	padding := strings.Repeat("x", 2*maxDumpBody)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"token": "eyJ.secret.jwt", "value": "` + padding + `"}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client, err := NewClientBuilder().
+		BaseURL(server.URL).
+		BasicAuth("testuser", "testpass").
+		Debug(true).
+		DebugLogger(logger).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.MakeRequest(context.Background(), utils.RequestOptions{
+		Method: http.MethodPost,
+		Path:   "/login",
+		Body:   map[string]string{"password": "hunter2", "value": padding},
+	})
+	if err != nil {
+		t.Fatalf("MakeRequest() returned error: %v", err)
+	}
+
+	dump := output.String()
+	for _, secret := range []string{"hunter2", "eyJ.secret.jwt"} {
+		if strings.Contains(dump, secret) {
+			t.Errorf("Debug output leaks %q", secret)
+		}
+	}
+	if !strings.Contains(dump, "(truncated)") {
+		t.Error("Expected the large bodies to be truncated")
+	}
+
+	if body := redactBody("text/plain", []byte("password=hunter2")); body != "<16 bytes omitted>" {
+		t.Errorf("Expected a body that cannot be parsed to be omitted, got %q", body)
+	}
+}
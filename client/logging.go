@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/errors"
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
+)
+
+// logAttempt emits one record describing an attempt of a request
+func logAttempt(ctx context.Context, logger *slog.Logger, opts utils.RequestOptions, resp *utils.Response, err error, duration time.Duration) {
+	attrs := []slog.Attr{
+		slog.String("method", opts.Method),
+		slog.String("path", opts.Path),
+		slog.Duration("duration", duration),
+	}
+	if opts.Operation != "" {
+		attrs = append(attrs, slog.String("operation", opts.Operation))
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	if attempt, ok := utils.AttemptFromContext(ctx); ok {
+		attrs = append(attrs, slog.Int("attempt", attempt.Number))
+		if attempt.RetryReason != "" {
+			attrs = append(attrs, slog.String("retry_reason", attempt.RetryReason))
+		}
+	}
+
+	if err == nil {
+		logger.LogAttrs(ctx, slog.LevelInfo, "dokan request", attrs...)
+		return
+	}
+
+	kind := errors.Kind(err)
+	attrs = append(attrs, slog.String("error_type", kind), slog.String("error", err.Error()))
+	logger.LogAttrs(ctx, attemptLevel(kind), "dokan request failed", attrs...)
+}
+
+// attemptLevel returns the level of the record of an attempt that failed
+// with an error of the given kind
+func attemptLevel(kind string) slog.Level {
+	switch kind {
+	case errors.KindClient, errors.KindNotFound, errors.KindAuthentication, errors.KindValidation,
+		errors.KindRateLimit, errors.KindCircuitOpen, errors.KindCanceled:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
+)
+
+func TestClientBuilder_Logger(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	client, err := NewClientBuilder().
+		BaseURL(server.URL).
+		BasicAuth("testuser", "testpass").
+		RetryPolicy(utils.DefaultRetryPolicy{BaseDelay: time.Millisecond}).
+		Logger(slog.New(slog.NewJSONHandler(&output, nil))).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.MakeRequest(context.Background(), utils.RequestOptions{Method: http.MethodGet, Path: "/products/1"})
+	if err != nil {
+		t.Fatalf("MakeRequest() returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected one record per attempt, got:\n%s", output.String())
+	}
+
+	var first, second map[string]interface{}
+	json.Unmarshal([]byte(lines[0]), &first)
+	json.Unmarshal([]byte(lines[1]), &second)
+
+	if first["level"] != "ERROR" || first["error_type"] != "server" || first["status"] != float64(503) || first["attempt"] != float64(1) {
+		t.Errorf("Unexpected first record: %v", first)
+	}
+	if second["level"] != "INFO" || second["retry_reason"] != "server" || second["attempt"] != float64(2) || second["path"] != "/products/1" {
+		t.Errorf("Unexpected second record: %v", second)
+	}
+	if _, ok := second["duration"]; !ok {
+		t.Errorf("Expected a duration, got %v", second)
+	}
+}
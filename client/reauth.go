@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/auth"
+)
+
+// reauthInterval is the minimum time between two refreshes triggered by 401
+// responses. It guards against refresh loops when the server keeps
+// rejecting the credentials.
+const reauthInterval = 30 * time.Second
+
+// reauthenticator refreshes the credentials of a client when a request is
+// rejected with 401 Unauthorized
+type reauthenticator struct {
+	auth      auth.Authenticator
+	onRefresh func(err error)
+
+	// generation counts successful refreshes, so that requests sent with
+	// credentials that were refreshed since are replayed without
+	// refreshing again
+	generation atomic.Uint64
+
+	mu   sync.Mutex
+	last time.Time
+	// refreshing is closed when the refresh in progress, if any, ends
+	refreshing chan struct{}
+}
+
+// setAuth replaces the authenticator. Requests sent with the previous one
+// are replayed with the new one without refreshing it.
+func (r *reauthenticator) setAuth(authenticator auth.Authenticator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.auth = authenticator
+	r.last = time.Time{}
+	r.generation.Add(1)
+}
+
+// current returns the generation of the credentials
+func (r *reauthenticator) current() uint64 {
+	return r.generation.Load()
+}
+
+// refresh refreshes the credentials after a request sent with generation
+// got a 401, unless another request already did, the last refresh was too
+// recent or the authenticator cannot refresh. It reports whether the request
+// should be replayed. Requests that find a refresh in progress wait for it,
+// giving up when ctx is done.
+func (r *reauthenticator) refresh(ctx context.Context, generation uint64) bool {
+	r.mu.Lock()
+	for r.refreshing != nil && r.generation.Load() == generation {
+		done := r.refreshing
+		r.mu.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return false
+		}
+		r.mu.Lock()
+	}
+	if r.generation.Load() != generation {
+		r.mu.Unlock()
+		return true
+	}
+	authenticator := r.auth
+	if (!r.last.IsZero() && time.Since(r.last) < reauthInterval) || !auth.Refreshable(authenticator) {
+		r.mu.Unlock()
+		return false
+	}
+	done := make(chan struct{})
+	r.refreshing = done
+	r.mu.Unlock()
+
+	err := auth.RefreshWithContext(ctx, authenticator)
+
+	// A refresh abandoned by its caller says nothing about the credentials
+	abandoned := err != nil && ctx.Err() != nil
+	r.mu.Lock()
+	r.refreshing = nil
+	if !abandoned {
+		r.last = time.Now()
+	}
+	if err == nil {
+		r.generation.Add(1)
+	}
+	r.mu.Unlock()
+	close(done)
+
+	if r.onRefresh != nil && !abandoned {
+		r.onRefresh(err)
+	}
+	return err == nil
+}
@@ -18,7 +18,7 @@
 //	product := &dokan.Product{
 //		Name:         "Example Product",
 //		Type:         dokan.ProductTypeSimple,
-//		RegularPrice: "29.99",
+//		RegularPrice: dokan.MustParseDecimal("29.99"),
 //		Status:       dokan.ProductStatusPublish,
 //	}
 //
@@ -32,11 +32,18 @@ package dokan
 
 import (
 	"github.com/diogenes-moreira/dokan-go-sdk/auth"
+	"github.com/diogenes-moreira/dokan-go-sdk/batch"
+	"github.com/diogenes-moreira/dokan-go-sdk/breaker"
 	"github.com/diogenes-moreira/dokan-go-sdk/client"
 	"github.com/diogenes-moreira/dokan-go-sdk/errors"
+	"github.com/diogenes-moreira/dokan-go-sdk/metrics"
 	"github.com/diogenes-moreira/dokan-go-sdk/orders"
+	"github.com/diogenes-moreira/dokan-go-sdk/pagination"
+	"github.com/diogenes-moreira/dokan-go-sdk/products"
+	"github.com/diogenes-moreira/dokan-go-sdk/ratelimit"
 	"github.com/diogenes-moreira/dokan-go-sdk/stores"
 	"github.com/diogenes-moreira/dokan-go-sdk/types"
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
 )
 
 // Re-export main types for easier access
@@ -46,17 +53,54 @@ type (
 	Config        = client.Config
 	ClientBuilder = client.ClientBuilder
 
+	// Middleware types
+	HTTPClient        = utils.HTTPClient
+	HTTPClientFunc    = utils.HTTPClientFunc
+	Middleware        = utils.Middleware
+	RequestFunc       = utils.RequestFunc
+	RequestMiddleware = utils.RequestMiddleware
+
+	// Retry types
+	RetryPolicy        = utils.RetryPolicy
+	RetryDecision      = utils.RetryDecision
+	DefaultRetryPolicy = utils.DefaultRetryPolicy
+	NoRetryPolicy      = utils.NoRetryPolicy
+	RetryBudget        = utils.RetryBudget
+
+	// Rate limit types
+	RateLimitConfig = ratelimit.Config
+	RateLimiter     = ratelimit.Limiter
+	RateLimitStats  = ratelimit.Stats
+
+	// Circuit breaker types
+	CircuitBreaker       = breaker.Breaker
+	CircuitBreakerConfig = breaker.Config
+	CircuitState         = breaker.State
+
+	// Metrics types
+	Metrics       = metrics.Metrics
+	MetricsLabels = metrics.Labels
+
 	// Product types
 	Product           = types.Product
 	ProductType       = types.ProductType
 	ProductStatus     = types.ProductStatus
 	CatalogVisibility = types.CatalogVisibility
+	StockStatus       = types.StockStatus
+	BackorderStatus   = types.BackorderStatus
+	TaxStatus         = types.TaxStatus
+	ProductDimensions = types.ProductDimensions
 	ProductCategory   = types.ProductCategory
 	ProductTag        = types.ProductTag
 	ProductImage      = types.ProductImage
 	ProductAttribute  = types.ProductAttribute
 	ProductListParams = types.ProductListParams
 
+	// Variation types
+	ProductVariation    = types.ProductVariation
+	VariationAttribute  = types.VariationAttribute
+	VariationListParams = types.VariationListParams
+
 	// Order types
 	Order           = types.Order
 	OrderStatus     = types.OrderStatus
@@ -79,13 +123,46 @@ type (
 	MetaData     = types.MetaData
 	ListParams   = types.ListParams
 	ListResponse = types.ListResponse
+	WPTime       = types.WPTime
+	FlexInt      = types.FlexInt
+	FlexFloat    = types.FlexFloat
+	FlexBool     = types.FlexBool
+
+	// Money types
+	Decimal         = types.Decimal
+	Money           = types.Money
+	RoundingMode    = types.RoundingMode
+	DecimalSettings = types.DecimalSettings
+
+	// Pagination types
+	PaginationOptions = pagination.Options
+
+	// Batch types
+	BatchRequest[T any]    = types.BatchRequest[T]
+	BatchResponse[T any]   = types.BatchResponse[T]
+	BatchOptions           = batch.Options
+	BatchResult[R any]     = batch.Result[R]
+	BatchItemResult[R any] = batch.ItemResult[R]
 
 	// Auth types
-	AuthType      = auth.AuthType
-	Authenticator = auth.Authenticator
-	BasicAuth     = auth.BasicAuth
-	JWTAuth       = auth.JWTAuth
-	AuthConfig    = auth.Config
+	AuthType                = auth.AuthType
+	Authenticator           = auth.Authenticator
+	BasicAuth               = auth.BasicAuth
+	JWTAuth                 = auth.JWTAuth
+	JWTLoginAuth            = auth.JWTLoginAuth
+	WooCommerceKeysAuth     = auth.WooCommerceKeysAuth
+	ApplicationPasswordAuth = auth.ApplicationPasswordAuth
+	CookieAuth              = auth.CookieAuth
+	OAuthSignatureMethod    = auth.OAuthSignatureMethod
+	AuthConfig              = auth.Config
+
+	// Credential provider types
+	CredentialProvider = auth.CredentialProvider
+	EnvProvider        = auth.EnvProvider
+	FileProvider       = auth.FileProvider
+	SecretDirProvider  = auth.SecretDirProvider
+	ChainProvider      = auth.ChainProvider
+	ProviderAuth       = auth.ProviderAuth
 
 	// Review types
 	ReviewListParams = stores.ReviewListParams
@@ -98,10 +175,28 @@ type (
 	ValidationError     = errors.ValidationError
 	NotFoundError       = errors.NotFoundError
 	RateLimitError      = errors.RateLimitError
+	CircuitOpenError    = errors.CircuitOpenError
 )
 
 // Re-export constants
 const (
+	// Version is the version of the SDK
+	Version = client.Version
+
+	// Retry decisions
+	RetryAbort    = utils.RetryAbort
+	RetrySafe     = utils.RetrySafe
+	RetryIfAbsent = utils.RetryIfAbsent
+
+	// ProductIdempotencyKeyMeta is the meta_data key used to deduplicate
+	// product creates that are retried
+	ProductIdempotencyKeyMeta = products.IdempotencyKeyMeta
+
+	// Circuit breaker states
+	CircuitClosed   = breaker.StateClosed
+	CircuitOpen     = breaker.StateOpen
+	CircuitHalfOpen = breaker.StateHalfOpen
+
 	// Product types
 	ProductTypeSimple   = types.ProductTypeSimple
 	ProductTypeGrouped  = types.ProductTypeGrouped
@@ -119,6 +214,30 @@ const (
 	CatalogVisibilitySearch  = types.CatalogVisibilitySearch
 	CatalogVisibilityHidden  = types.CatalogVisibilityHidden
 
+	// Rounding modes
+	RoundHalfUp   = types.RoundHalfUp
+	RoundHalfEven = types.RoundHalfEven
+	RoundHalfDown = types.RoundHalfDown
+	RoundUp       = types.RoundUp
+	RoundDown     = types.RoundDown
+	RoundCeiling  = types.RoundCeiling
+	RoundFloor    = types.RoundFloor
+
+	// Stock statuses
+	StockStatusInStock     = types.StockStatusInStock
+	StockStatusOutOfStock  = types.StockStatusOutOfStock
+	StockStatusOnBackorder = types.StockStatusOnBackorder
+
+	// Backorder options
+	BackordersNo     = types.BackordersNo
+	BackordersNotify = types.BackordersNotify
+	BackordersYes    = types.BackordersYes
+
+	// Tax statuses
+	TaxStatusTaxable  = types.TaxStatusTaxable
+	TaxStatusShipping = types.TaxStatusShipping
+	TaxStatusNone     = types.TaxStatusNone
+
 	// Order statuses
 	OrderStatusPending    = types.OrderStatusPending
 	OrderStatusProcessing = types.OrderStatusProcessing
@@ -129,10 +248,25 @@ const (
 	OrderStatusFailed     = types.OrderStatusFailed
 
 	// Auth types
-	AuthTypeBasic = auth.AuthTypeBasic
-	AuthTypeJWT   = auth.AuthTypeJWT
+	AuthTypeBasic               = auth.AuthTypeBasic
+	AuthTypeJWT                 = auth.AuthTypeJWT
+	AuthTypeWooCommerceKeys     = auth.AuthTypeWooCommerceKeys
+	AuthTypeApplicationPassword = auth.AuthTypeApplicationPassword
+	AuthTypeCookie              = auth.AuthTypeCookie
+
+	// OAuth signature methods
+	OAuthHMACSHA1   = auth.OAuthHMACSHA1
+	OAuthHMACSHA256 = auth.OAuthHMACSHA256
 )
 
+// ErrCircuitOpen is matched by errors.Is when a request is rejected by an
+// open circuit breaker
+var ErrCircuitOpen = errors.ErrCircuitOpen
+
+// ErrNoCredentials is matched by errors.Is when a credential provider finds
+// no credentials
+var ErrNoCredentials = auth.ErrNoCredentials
+
 // Re-export main functions
 var (
 	// Client functions
@@ -140,10 +274,51 @@ var (
 	NewClientBuilder = client.NewClientBuilder
 	DefaultConfig    = client.DefaultConfig
 
+	// Retry functions
+	WithRetries     = utils.WithRetries
+	WithRetryPolicy = utils.WithRetryPolicy
+	NewRetryBudget  = utils.NewRetryBudget
+
+	// Rate limit functions
+	NewRateLimiter = ratelimit.New
+
+	// Circuit breaker functions
+	NewCircuitBreaker = breaker.New
+
+	// Type functions
+	NewWPTime   = types.NewWPTime
+	ParseWPTime = types.ParseWPTime
+
+	// Product functions
+	VariationMatrix = products.VariationMatrix
+
+	// Money functions
+	NewDecimal             = types.NewDecimal
+	NewDecimalFromInt      = types.NewDecimalFromInt
+	NewDecimalFromFloat    = types.NewDecimalFromFloat
+	ParseDecimal           = types.ParseDecimal
+	MustParseDecimal       = types.MustParseDecimal
+	SumDecimals            = types.SumDecimals
+	NewMoney               = types.NewMoney
+	CurrencyDecimals       = types.CurrencyDecimals
+	DefaultDecimalSettings = types.DefaultDecimalSettings
+
 	// Auth functions
-	NewBasicAuth     = auth.NewBasicAuth
-	NewJWTAuth       = auth.NewJWTAuth
-	NewAuthenticator = auth.NewAuthenticator
+	NewBasicAuth               = auth.NewBasicAuth
+	NewJWTAuth                 = auth.NewJWTAuth
+	NewJWTLoginAuth            = auth.NewJWTLoginAuth
+	NewWooCommerceKeysAuth     = auth.NewWooCommerceKeysAuth
+	NewApplicationPasswordAuth = auth.NewApplicationPasswordAuth
+	NewCookieAuth              = auth.NewCookieAuth
+	NewAuthenticator           = auth.NewAuthenticator
+
+	// Credential provider functions
+	NewEnvProvider         = auth.NewEnvProvider
+	NewFileProvider        = auth.NewFileProvider
+	NewSecretDirProvider   = auth.NewSecretDirProvider
+	NewChainProvider       = auth.NewChainProvider
+	NewProviderAuth        = auth.NewProviderAuth
+	DefaultCredentialsFile = auth.DefaultCredentialsFile
 
 	// Error functions
 	NewDokanError          = errors.NewDokanError
@@ -152,6 +327,8 @@ var (
 	NewValidationError     = errors.NewValidationError
 	NewNotFoundError       = errors.NewNotFoundError
 	NewRateLimitError      = errors.NewRateLimitError
+	NewCircuitOpenError    = errors.NewCircuitOpenError
 	IsDokanError           = errors.IsDokanError
+	ErrorKind              = errors.Kind
 	HandleHTTPError        = errors.HandleHTTPError
 )
@@ -0,0 +1,224 @@
+// Package dokanotel instruments a Dokan client with OpenTelemetry tracing.
+//
+// Every call to Client.MakeRequest gets a client span named after the API
+// operation, such as "products.update", with a child span for each HTTP
+// attempt, so retries show up as siblings under the same parent. Attempts
+// carry W3C trace-context headers so that the WordPress side can join the
+// trace.
+//
+// The package is a separate module so that the core SDK does not depend on
+// OpenTelemetry:
+//
+//	client, err := dokanotel.Instrument(dokan.NewClientBuilder().
+//		BaseURL("https://example.com").
+//		BasicAuth("username", "password")).
+//		Build()
+package dokanotel
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/client"
+	"github.com/diogenes-moreira/dokan-go-sdk/errors"
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
+)
+
+// ScopeName is the instrumentation scope of the tracer
+const ScopeName = "github.com/diogenes-moreira/dokan-go-sdk/dokanotel"
+
+// Attribute keys specific to Dokan. Resource IDs are reported as
+// "dokan.<name>", e.g. "dokan.product_id".
+const (
+	OperationKey   = attribute.Key("dokan.operation")
+	ResourceKey    = attribute.Key("dokan.resource")
+	RetryReasonKey = attribute.Key("dokan.retry_reason")
+)
+
+// Semantic convention attribute keys for HTTP client spans
+const (
+	httpRequestMethodKey      = attribute.Key("http.request.method")
+	httpResponseStatusCodeKey = attribute.Key("http.response.status_code")
+	httpResendCountKey        = attribute.Key("http.request.resend_count")
+	urlFullKey                = attribute.Key("url.full")
+	urlPathKey                = attribute.Key("url.path")
+	serverAddressKey          = attribute.Key("server.address")
+	serverPortKey             = attribute.Key("server.port")
+	errorTypeKey              = attribute.Key("error.type")
+)
+
+// Option configures an Instrumentation
+type Option func(*Instrumentation)
+
+// WithTracerProvider sets the tracer provider. Defaults to the global one.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(i *Instrumentation) {
+		i.provider = provider
+	}
+}
+
+// WithPropagator sets the propagator that injects trace context into
+// requests. Defaults to W3C trace context and baggage.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(i *Instrumentation) {
+		i.propagator = propagator
+	}
+}
+
+// Instrumentation creates the spans of a Dokan client
+type Instrumentation struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+	tracer     trace.Tracer
+}
+
+// New creates an Instrumentation
+func New(opts ...Option) *Instrumentation {
+	i := &Instrumentation{}
+	for _, opt := range opts {
+		opt(i)
+	}
+	if i.provider == nil {
+		i.provider = otel.GetTracerProvider()
+	}
+	if i.propagator == nil {
+		i.propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	}
+	i.tracer = i.provider.Tracer(ScopeName)
+	return i
+}
+
+// Instrument adds tracing to the client built by builder
+func Instrument(builder *client.ClientBuilder, opts ...Option) *client.ClientBuilder {
+	i := New(opts...)
+	return builder.UseRequest(i.RequestMiddleware()).Use(i.Middleware())
+}
+
+// RequestMiddleware returns middleware that wraps every call to MakeRequest
+// in a span
+func (i *Instrumentation) RequestMiddleware() utils.RequestMiddleware {
+	return func(next utils.RequestFunc) utils.RequestFunc {
+		return func(ctx context.Context, opts utils.RequestOptions) (*utils.Response, error) {
+			method := opts.Method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			name := opts.Operation
+			if name == "" {
+				name = method
+			}
+
+			attrs := []attribute.KeyValue{
+				httpRequestMethodKey.String(method),
+				urlPathKey.String(opts.Path),
+			}
+			if opts.Operation != "" {
+				attrs = append(attrs, OperationKey.String(opts.Operation))
+				resource, _, _ := strings.Cut(opts.Operation, ".")
+				attrs = append(attrs, ResourceKey.String(resource))
+			}
+			for key, id := range opts.ResourceIDs {
+				attrs = append(attrs, attribute.Int("dokan."+key, id))
+			}
+
+			ctx, span := i.tracer.Start(ctx, name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+			)
+			defer span.End()
+
+			resp, err := next(ctx, opts)
+			if resp != nil {
+				span.SetAttributes(httpResponseStatusCodeKey.Int(resp.StatusCode))
+			}
+			if err != nil {
+				span.RecordError(err)
+				span.SetAttributes(errorTypeKey.String(errors.Kind(err)))
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return resp, err
+		}
+	}
+}
+
+// Middleware returns HTTP middleware that wraps every attempt in a span and
+// injects the trace context into its headers
+func (i *Instrumentation) Middleware() utils.Middleware {
+	return func(next utils.HTTPClient) utils.HTTPClient {
+		return utils.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+			attrs := []attribute.KeyValue{
+				httpRequestMethodKey.String(req.Method),
+				urlFullKey.String(redactURL(req.URL)),
+				serverAddressKey.String(req.URL.Hostname()),
+			}
+			if port := serverPort(req.URL); port > 0 {
+				attrs = append(attrs, serverPortKey.Int(port))
+			}
+			if attempt, ok := utils.AttemptFromContext(req.Context()); ok && attempt.Number > 1 {
+				attrs = append(attrs,
+					httpResendCountKey.Int(attempt.Number-1),
+					RetryReasonKey.String(attempt.RetryReason),
+				)
+			}
+
+			ctx, span := i.tracer.Start(req.Context(), req.Method,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+			)
+			defer span.End()
+
+			req = req.WithContext(ctx)
+			i.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			resp, err := next.Do(req)
+			if err != nil {
+				span.RecordError(err)
+				span.SetAttributes(errorTypeKey.String(errors.Kind(errors.NewNetworkError(err))))
+				span.SetStatus(codes.Error, err.Error())
+				return resp, err
+			}
+
+			span.SetAttributes(httpResponseStatusCodeKey.Int(resp.StatusCode))
+			if resp.StatusCode >= 400 {
+				span.SetAttributes(errorTypeKey.String(strconv.Itoa(resp.StatusCode)))
+				span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+			}
+			return resp, nil
+		})
+	}
+}
+
+// redactURL returns u without user info or query string, which may carry
+// credentials
+func redactURL(u *url.URL) string {
+	clone := *u
+	clone.User = nil
+	clone.RawQuery = ""
+	clone.ForceQuery = false
+	return clone.String()
+}
+
+// serverPort returns the port of u, or the default port of its scheme
+func serverPort(u *url.URL) int {
+	if port := u.Port(); port != "" {
+		n, _ := strconv.Atoi(port)
+		return n
+	}
+	switch u.Scheme {
+	case "https":
+		return 443
+	case "http":
+		return 80
+	}
+	return 0
+}
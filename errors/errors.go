@@ -1,8 +1,14 @@
 package errors
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // DokanError represents a Dokan API error
@@ -11,6 +17,9 @@ type DokanError struct {
 	Message    string      `json:"message"`
 	Data       interface{} `json:"data,omitempty"`
 	StatusCode int         `json:"-"`
+	// RetryAfter is the delay in seconds requested by the server through
+	// the Retry-After header, typically on 503 responses, or 0 if none
+	RetryAfter int `json:"-"`
 }
 
 // Error implements the error interface
@@ -27,6 +36,10 @@ func IsDokanError(err error) bool {
 // NetworkError represents a network-related error
 type NetworkError struct {
 	Err error
+	// RequestWritten reports whether the request may have reached the
+	// server. It is false only when the request is known not to have been
+	// fully written, which makes it safe to retry even non-idempotent calls.
+	RequestWritten bool
 }
 
 func (e *NetworkError) Error() string {
@@ -69,13 +82,37 @@ func (e *NotFoundError) Error() string {
 
 // RateLimitError represents a rate limit exceeded error
 type RateLimitError struct {
+	// RetryAfter is the delay in seconds from the Retry-After header, or 0
+	// when the server did not send one
 	RetryAfter int
 }
 
 func (e *RateLimitError) Error() string {
+	if e.RetryAfter <= 0 {
+		return "rate limit exceeded"
+	}
 	return fmt.Sprintf("rate limit exceeded, retry after %d seconds", e.RetryAfter)
 }
 
+// ErrCircuitOpen is matched by errors.Is for every CircuitOpenError
+var ErrCircuitOpen = stderrors.New("circuit breaker is open")
+
+// CircuitOpenError is returned without sending the request while the
+// circuit breaker guarding the backend is open
+type CircuitOpenError struct {
+	// OpenUntil is when the breaker will let a trial request through
+	OpenUntil time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker is open until %s", e.OpenUntil.Format(time.RFC3339))
+}
+
+// Is reports whether target is ErrCircuitOpen
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
 // NewDokanError creates a new DokanError
 func NewDokanError(code, message string, statusCode int) *DokanError {
 	return &DokanError{
@@ -112,13 +149,83 @@ func NewNotFoundError(resource string, id interface{}) *NotFoundError {
 	}
 }
 
+// NewCircuitOpenError creates a new CircuitOpenError
+func NewCircuitOpenError(openUntil time.Time) *CircuitOpenError {
+	return &CircuitOpenError{OpenUntil: openUntil}
+}
+
 // NewRateLimitError creates a new RateLimitError
 func NewRateLimitError(retryAfter int) *RateLimitError {
 	return &RateLimitError{RetryAfter: retryAfter}
 }
 
+// Kinds of errors reported by Kind
+const (
+	KindNetwork        = "network"
+	KindRateLimit      = "rate_limit"
+	KindServer         = "server"
+	KindClient         = "client"
+	KindAuthentication = "authentication"
+	KindValidation     = "validation"
+	KindNotFound       = "not_found"
+	KindCircuitOpen    = "circuit_open"
+	KindCanceled       = "canceled"
+	KindOther          = "other"
+)
+
+// Kind returns a short, stable name for the kind of err, suitable for log
+// fields and metric labels, or "" if err is nil
+func Kind(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var (
+		networkErr    *NetworkError
+		rateLimitErr  *RateLimitError
+		authErr       *AuthenticationError
+		validationErr *ValidationError
+		notFoundErr   *NotFoundError
+		dokanErr      *DokanError
+	)
+	switch {
+	case stderrors.Is(err, context.Canceled):
+		return KindCanceled
+	case stderrors.Is(err, ErrCircuitOpen):
+		return KindCircuitOpen
+	case stderrors.As(err, &networkErr):
+		return KindNetwork
+	case stderrors.As(err, &rateLimitErr):
+		return KindRateLimit
+	case stderrors.As(err, &authErr):
+		return KindAuthentication
+	case stderrors.As(err, &validationErr):
+		return KindValidation
+	case stderrors.As(err, &notFoundErr):
+		return KindNotFound
+	case stderrors.As(err, &dokanErr):
+		switch {
+		case dokanErr.StatusCode == http.StatusTooManyRequests:
+			return KindRateLimit
+		case dokanErr.StatusCode >= 500:
+			return KindServer
+		case dokanErr.StatusCode >= 400:
+			return KindClient
+		}
+	}
+	return KindOther
+}
+
 // HandleHTTPError converts HTTP status codes to appropriate errors
 func HandleHTTPError(statusCode int, body []byte) error {
+	return HandleHTTPResponse(statusCode, nil, body)
+}
+
+// HandleHTTPResponse converts HTTP status codes to appropriate errors, using
+// the Retry-After header to fill in the retry delay
+func HandleHTTPResponse(statusCode int, header http.Header, body []byte) error {
+	retryAfter := RetryAfterSeconds(header, time.Now())
+
 	switch statusCode {
 	case http.StatusUnauthorized:
 		return NewAuthenticationError("unauthorized access")
@@ -127,16 +234,85 @@ func HandleHTTPError(statusCode int, body []byte) error {
 	case http.StatusNotFound:
 		return NewNotFoundError("resource", "unknown")
 	case http.StatusTooManyRequests:
-		return NewRateLimitError(60) // Default retry after 60 seconds
+		return NewRateLimitError(retryAfter)
 	case http.StatusBadRequest:
 		return NewDokanError("bad_request", "bad request", statusCode)
 	case http.StatusInternalServerError:
 		return NewDokanError("internal_error", "internal server error", statusCode)
 	default:
 		if statusCode >= 400 {
-			return NewDokanError("http_error", fmt.Sprintf("HTTP %d error", statusCode), statusCode)
+			err := NewDokanError("http_error", fmt.Sprintf("HTTP %d error", statusCode), statusCode)
+			err.RetryAfter = retryAfter
+			return err
 		}
 		return nil
 	}
 }
 
+// ParseRetryAfter parses the value of a Retry-After header, given either as
+// a number of seconds or as an HTTP-date. Dates in the past yield a zero
+// delay, and delays too long for a time.Duration are clamped to whole
+// seconds below its maximum. ok is false when the value is empty or
+// malformed.
+func ParseRetryAfter(value string, now time.Time) (delay time.Duration, ok bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseUint(value, 10, 64); err == nil || isOutOfRange(err) {
+		return time.Duration(min(seconds, maxRetryAfterSeconds)) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	delay = date.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+	return min(delay, time.Duration(maxRetryAfterSeconds)*time.Second), true
+}
+
+// maxRetryAfterSeconds is the longest Retry-After delay, in seconds, that
+// fits in a time.Duration
+const maxRetryAfterSeconds = uint64(math.MaxInt64 / time.Second)
+
+// isOutOfRange reports whether err is a strconv error for a number too
+// large for its type
+func isOutOfRange(err error) bool {
+	var numErr *strconv.NumError
+	return stderrors.As(err, &numErr) && numErr.Err == strconv.ErrRange
+}
+
+// RetryAfterSeconds returns the Retry-After delay of header in whole
+// seconds, rounded up, or 0 if the header is missing or malformed
+func RetryAfterSeconds(header http.Header, now time.Time) int {
+	if header == nil {
+		return 0
+	}
+
+	delay, ok := ParseRetryAfter(header.Get("Retry-After"), now)
+	if !ok {
+		return 0
+	}
+	return int(math.Ceil(delay.Seconds()))
+}
+
+// RetryAfter returns the delay requested by the server for err, if err is a
+// RateLimitError or a DokanError carrying a Retry-After delay
+func RetryAfter(err error) (time.Duration, bool) {
+	var rateLimitErr *RateLimitError
+	if stderrors.As(err, &rateLimitErr) && rateLimitErr.RetryAfter > 0 {
+		return time.Duration(rateLimitErr.RetryAfter) * time.Second, true
+	}
+
+	var dokanErr *DokanError
+	if stderrors.As(err, &dokanErr) && dokanErr.RetryAfter > 0 {
+		return time.Duration(dokanErr.RetryAfter) * time.Second, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{" 0 ", 0, true},
+		{"Tue, 02 Jan 2024 15:04:35 GMT", 30 * time.Second, true},
+		{"Tue, 02 Jan 2024 15:00:00 GMT", 0, true},
+		{"", 0, false},
+		{"-5", 0, false},
+		{"99999999999999999999", time.Duration(maxRetryAfterSeconds) * time.Second, true},
+		{"9223372036854775807", time.Duration(maxRetryAfterSeconds) * time.Second, true},
+		{"Fri, 31 Dec 9999 23:59:59 GMT", time.Duration(maxRetryAfterSeconds) * time.Second, true},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		delay, ok := ParseRetryAfter(tt.value, now)
+		if ok != tt.ok || delay != tt.expected {
+			t.Errorf("ParseRetryAfter(%q) = %v, %v; expected %v, %v", tt.value, delay, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestHandleHTTPResponse_RetryAfter(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", "7")
+
+	err := HandleHTTPResponse(http.StatusTooManyRequests, header, nil)
+	rateLimitErr, ok := err.(*RateLimitError)
+	if !ok {
+		t.Fatalf("Expected RateLimitError, got %T", err)
+	}
+	if rateLimitErr.RetryAfter != 7 {
+		t.Errorf("Expected RetryAfter 7, got %d", rateLimitErr.RetryAfter)
+	}
+
+	err = HandleHTTPResponse(http.StatusServiceUnavailable, header, nil)
+	if delay, ok := RetryAfter(err); !ok || delay != 7*time.Second {
+		t.Errorf("Expected a 7s delay on 503, got %v, %v", delay, ok)
+	}
+
+	if _, ok := RetryAfter(HandleHTTPError(http.StatusTooManyRequests, nil)); ok {
+		t.Error("Expected no delay without a Retry-After header")
+	}
+}
+
+func TestKind(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{nil, ""},
+		{NewNetworkError(context.DeadlineExceeded), KindNetwork},
+		{NewNetworkError(context.Canceled), KindCanceled},
+		{NewRateLimitError(0), KindRateLimit},
+		{NewDokanError("too_many", "slow down", 429), KindRateLimit},
+		{NewDokanError("internal_error", "oops", 500), KindServer},
+		{NewDokanError("bad_request", "bad", 400), KindClient},
+		{fmt.Errorf("failed to get product: %w", NewNotFoundError("product", 1)), KindNotFound},
+		{NewAuthenticationError("unauthorized access"), KindAuthentication},
+		{NewValidationError("name", "required", "name is required"), KindValidation},
+		{NewCircuitOpenError(time.Now()), KindCircuitOpen},
+		{fmt.Errorf("boom"), KindOther},
+	}
+
+	for _, tt := range tests {
+		if got := Kind(tt.err); got != tt.expected {
+			t.Errorf("Kind(%v) = %q, expected %q", tt.err, got, tt.expected)
+		}
+	}
+}
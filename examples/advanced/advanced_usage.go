@@ -50,7 +50,7 @@ func main() {
 		fmt.Printf("Working with store: %s (ID: %d)\n", store.StoreName, store.ID)
 
 		// Get products for this store
-		storeProducts, err := client.Stores.GetProducts(ctx, store.ID, &dokan.ProductListParams{
+		storeProducts, err := client.Stores.GetProducts(ctx, store.ID.Int(), &dokan.ProductListParams{
 			ListParams: dokan.ListParams{
 				Page:    1,
 				PerPage: 5,
@@ -66,7 +66,7 @@ func main() {
 		}
 
 		// Get reviews for this store
-		storeReviews, err := client.Stores.GetReviews(ctx, store.ID, &dokan.ReviewListParams{
+		storeReviews, err := client.Stores.GetReviews(ctx, store.ID.Int(), &dokan.ReviewListParams{
 			ListParams: dokan.ListParams{
 				Page:    1,
 				PerPage: 3,
@@ -88,21 +88,21 @@ func main() {
 		{
 			Name:         "Bulk Product 1",
 			Type:         dokan.ProductTypeSimple,
-			RegularPrice: "19.99",
+			RegularPrice: dokan.MustParseDecimal("19.99"),
 			Status:       dokan.ProductStatusDraft,
 			SKU:          "BULK-001",
 		},
 		{
 			Name:         "Bulk Product 2",
 			Type:         dokan.ProductTypeSimple,
-			RegularPrice: "29.99",
+			RegularPrice: dokan.MustParseDecimal("29.99"),
 			Status:       dokan.ProductStatusDraft,
 			SKU:          "BULK-002",
 		},
 		{
 			Name:         "Bulk Product 3",
 			Type:         dokan.ProductTypeSimple,
-			RegularPrice: "39.99",
+			RegularPrice: dokan.MustParseDecimal("39.99"),
 			Status:       dokan.ProductStatusDraft,
 			SKU:          "BULK-003",
 		},
@@ -134,7 +134,7 @@ func main() {
 		product.Status = dokan.ProductStatusPublish
 		product.Description = fmt.Sprintf("This is product #%d created in bulk using the Dokan Go SDK", i+1)
 		
-		updated, err := client.Products.Update(ctx, product.ID, product)
+		updated, err := client.Products.Update(ctx, product.ID.Int(), product)
 		if err != nil {
 			log.Printf("Failed to update product %d: %v", product.ID, err)
 			continue
@@ -220,7 +220,7 @@ func main() {
 	for i, product := range createdProducts {
 		fmt.Printf("Deleting product %d/%d: %s\n", i+1, len(createdProducts), product.Name)
 		
-		err := client.Products.Delete(ctx, product.ID)
+		err := client.Products.Delete(ctx, product.ID.Int())
 		if err != nil {
 			log.Printf("Failed to delete product %d: %v", product.ID, err)
 			continue
@@ -28,8 +28,8 @@ func main() {
 	product := &dokan.Product{
 		Name:         "Amazing Go SDK Product",
 		Type:         dokan.ProductTypeSimple,
-		RegularPrice: "99.99",
-		SalePrice:    "79.99",
+		RegularPrice: dokan.MustParseDecimal("99.99"),
+		SalePrice:    dokan.MustParseDecimal("79.99"),
 		Description:  "This product was created using the Dokan Go SDK!",
 		ShortDescription: "Created with Go SDK",
 		Status:       dokan.ProductStatusPublish,
@@ -85,7 +85,7 @@ func main() {
 	// Example 3: Get a specific product
 	if createdProduct != nil {
 		fmt.Println("\n=== Getting specific product ===")
-		retrievedProduct, err := client.Products.Get(ctx, createdProduct.ID)
+		retrievedProduct, err := client.Products.Get(ctx, createdProduct.ID.Int())
 		if err != nil {
 			log.Printf("Failed to get product: %v", err)
 		} else {
@@ -151,9 +151,9 @@ func main() {
 	if createdProduct != nil {
 		fmt.Println("\n=== Updating product ===")
 		createdProduct.Description = "Updated description using the Dokan Go SDK!"
-		createdProduct.RegularPrice = "109.99"
+		createdProduct.RegularPrice = dokan.MustParseDecimal("109.99")
 		
-		updatedProduct, err := client.Products.Update(ctx, createdProduct.ID, createdProduct)
+		updatedProduct, err := client.Products.Update(ctx, createdProduct.ID.Int(), createdProduct)
 		if err != nil {
 			log.Printf("Failed to update product: %v", err)
 		} else {
@@ -27,8 +27,7 @@ type InventoryItem struct {
 func main() {
 	// Configurar cliente
 	client, err := dokan.NewClientBuilder().
-		BaseURL(os.Getenv("DOKAN_BASE_URL")).
-		BasicAuth(os.Getenv("DOKAN_USERNAME"), os.Getenv("DOKAN_PASSWORD")).
+		Credentials(dokan.NewChainProvider(dokan.NewEnvProvider(), dokan.NewFileProvider("", ""))).
 		Timeout(60 * time.Second).
 		RetryCount(3).
 		Build()
@@ -156,30 +155,19 @@ func readInventoryFromCSV(filename string) ([]InventoryItem, error) {
 // getAllProducts obtiene todos los productos de Dokan
 func getAllProducts(client *dokan.Client, ctx context.Context) ([]dokan.Product, error) {
 	var allProducts []dokan.Product
-	page := 1
-	perPage := 100
-
-	for {
-		params := &dokan.ProductListParams{
-			ListParams: dokan.ListParams{
-				Page:    page,
-				PerPage: perPage,
-			},
-		}
-
-		result, err := client.Products.List(ctx, params)
-		if err != nil {
-			return nil, fmt.Errorf("error obteniendo productos página %d: %w", page, err)
-		}
 
-		allProducts = append(allProducts, result.Products...)
+	params := &dokan.ProductListParams{
+		ListParams: dokan.ListParams{
+			PerPage: 100,
+		},
+	}
 
-		if page >= result.TotalPages {
-			break
+	// El iterador recorre todas las páginas usando X-WP-TotalPages
+	for product, err := range client.Products.All(ctx, params, nil) {
+		if err != nil {
+			return nil, fmt.Errorf("error obteniendo productos: %w", err)
 		}
-
-		page++
-		time.Sleep(200 * time.Millisecond) // Pausa entre páginas
+		allProducts = append(allProducts, product)
 	}
 
 	return allProducts, nil
@@ -190,7 +178,7 @@ func needsUpdate(existing *dokan.Product, item InventoryItem) bool {
 	if existing.Name != item.Name {
 		return true
 	}
-	if existing.RegularPrice != item.Price {
+	if price, err := dokan.ParseDecimal(item.Price); err != nil || !existing.RegularPrice.Equal(price) {
 		return true
 	}
 	if existing.Description != item.Description {
@@ -199,15 +187,23 @@ func needsUpdate(existing *dokan.Product, item InventoryItem) bool {
 	if existing.Featured != item.Featured {
 		return true
 	}
+	if !existing.ManageStock || existing.Stock() != item.Stock {
+		return true
+	}
 	return false
 }
 
 // createProduct crea un nuevo producto en Dokan
 func createProduct(client *dokan.Client, ctx context.Context, item InventoryItem) error {
+	price, err := dokan.ParseDecimal(item.Price)
+	if err != nil {
+		return fmt.Errorf("precio inválido %q: %w", item.Price, err)
+	}
+
 	product := &dokan.Product{
 		Name:              item.Name,
 		Type:              dokan.ProductTypeSimple,
-		RegularPrice:      item.Price,
+		RegularPrice:      price,
 		Description:       item.Description,
 		ShortDescription:  fmt.Sprintf("Producto %s - Stock: %d", item.Name, item.Stock),
 		Status:            dokan.ProductStatusPublish,
@@ -216,10 +212,13 @@ func createProduct(client *dokan.Client, ctx context.Context, item InventoryItem
 		SKU:               item.SKU,
 	}
 
+	// Gestionar stock con la cantidad del inventario
+	product.SetStockQuantity(item.Stock)
+
 	// Agregar categoría si está especificada
 	if item.CategoryID > 0 {
 		product.Categories = []dokan.ProductCategory{
-			{ID: item.CategoryID},
+			{ID: dokan.FlexInt(item.CategoryID)},
 		}
 	}
 
@@ -234,17 +233,23 @@ func createProduct(client *dokan.Client, ctx context.Context, item InventoryItem
 		}
 	}
 
-	_, err := client.Products.Create(ctx, product)
+	_, err = client.Products.Create(ctx, product)
 	return err
 }
 
 // updateProduct actualiza un producto existente
 func updateProduct(client *dokan.Client, ctx context.Context, existing *dokan.Product, item InventoryItem) error {
+	price, err := dokan.ParseDecimal(item.Price)
+	if err != nil {
+		return fmt.Errorf("precio inválido %q: %w", item.Price, err)
+	}
+
 	// Actualizar campos que han cambiado
 	existing.Name = item.Name
-	existing.RegularPrice = item.Price
+	existing.RegularPrice = price
 	existing.Description = item.Description
 	existing.Featured = item.Featured
+	existing.SetStockQuantity(item.Stock)
 
 	// Actualizar imagen si es diferente
 	if item.ImageURL != "" {
@@ -267,7 +272,7 @@ func updateProduct(client *dokan.Client, ctx context.Context, existing *dokan.Pr
 		}
 	}
 
-	_, err := client.Products.Update(ctx, existing.ID, existing)
+	_, err = client.Products.Update(ctx, existing.ID.Int(), existing)
 	return err
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"strings"
 	"time"
@@ -38,10 +39,10 @@ type OrderAction struct {
 func main() {
 	// Configurar cliente
 	client, err := dokan.NewClientBuilder().
-		BaseURL(os.Getenv("DOKAN_BASE_URL")).
-		BasicAuth(os.Getenv("DOKAN_USERNAME"), os.Getenv("DOKAN_PASSWORD")).
+		Credentials(dokan.NewChainProvider(dokan.NewEnvProvider(), dokan.NewFileProvider("", ""))).
 		Timeout(60 * time.Second).
 		RetryCount(3).
+		Logger(slog.Default()). // Un registro por petición en el log configurado abajo
 		Build()
 	if err != nil {
 		log.Fatalf("Error creando cliente: %v", err)
@@ -169,7 +170,7 @@ func (p *OrderProcessor) getPendingOrders(ctx context.Context) ([]dokan.Order, e
 // processOrder procesa una orden individual
 func (p *OrderProcessor) processOrder(ctx context.Context, order dokan.Order) (OrderAction, error) {
 	action := OrderAction{
-		OrderID:   order.ID,
+		OrderID:   order.ID.Int(),
 		Timestamp: time.Now(),
 	}
 
@@ -180,7 +181,7 @@ func (p *OrderProcessor) processOrder(ctx context.Context, order dokan.Order) (O
 		action.NewStatus = dokan.OrderStatusCancelled
 
 		// Cancelar orden
-		if err := p.updateOrderStatus(ctx, order.ID, dokan.OrderStatusCancelled); err != nil {
+		if err := p.updateOrderStatus(ctx, order.ID.Int(), dokan.OrderStatusCancelled); err != nil {
 			return action, fmt.Errorf("error cancelando orden: %w", err)
 		}
 
@@ -195,7 +196,7 @@ func (p *OrderProcessor) processOrder(ctx context.Context, order dokan.Order) (O
 		action.NewStatus = dokan.OrderStatusOnHold
 
 		// Poner en espera
-		if err := p.updateOrderStatus(ctx, order.ID, dokan.OrderStatusOnHold); err != nil {
+		if err := p.updateOrderStatus(ctx, order.ID.Int(), dokan.OrderStatusOnHold); err != nil {
 			return action, fmt.Errorf("error poniendo orden en espera: %w", err)
 		}
 
@@ -219,7 +220,7 @@ func (p *OrderProcessor) processOrder(ctx context.Context, order dokan.Order) (O
 		action.Reason = "Orden aprobada automáticamente"
 		action.NewStatus = dokan.OrderStatusProcessing
 
-		if err := p.updateOrderStatus(ctx, order.ID, dokan.OrderStatusProcessing); err != nil {
+		if err := p.updateOrderStatus(ctx, order.ID.Int(), dokan.OrderStatusProcessing); err != nil {
 			return action, fmt.Errorf("error aprobando orden: %w", err)
 		}
 
@@ -244,7 +245,7 @@ func (p *OrderProcessor) validateOrder(order dokan.Order) error {
 	}
 
 	// Verificar que tenga total válido
-	if order.Total == "" || order.Total == "0" {
+	if order.Total.Sign() <= 0 {
 		return fmt.Errorf("orden sin total válido")
 	}
 
@@ -276,7 +277,7 @@ func (p *OrderProcessor) checkInventory(order dokan.Order) error {
 
 	for _, item := range order.LineItems {
 		// Obtener información del producto
-		product, err := p.client.Products.Get(ctx, item.ProductID)
+		product, err := p.client.Products.Get(ctx, item.ProductID.Int())
 		if err != nil {
 			return fmt.Errorf("error obteniendo producto %d: %w", item.ProductID, err)
 		}
@@ -1,3 +1,5 @@
 module github.com/diogenes-moreira/dokan-go-sdk
 
-go 1.21.5
+go 1.24
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,84 @@
+// Package metrics defines how a Dokan client reports measurements of its
+// requests, so that they can be exported to a monitoring system such as
+// Prometheus without the SDK depending on it.
+package metrics
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/errors"
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
+)
+
+// Labels identify the requests a measurement refers to
+type Labels struct {
+	// Service is the API resource, e.g. "products"
+	Service string
+	// Operation is the API operation, e.g. "products.update"
+	Operation string
+}
+
+// Metrics receives measurements of every attempt of a request sent by a
+// client. Attempts rejected by the circuit breaker or the rate limiter are
+// not sent and not measured, and durations exclude the wait for the rate
+// limiter. Implementations must be safe for concurrent use.
+type Metrics interface {
+	// RequestStarted is called before an attempt is sent
+	RequestStarted(labels Labels)
+	// RequestFinished is called after an attempt with its status, which is
+	// the HTTP status code or, if no response was received, the errors.Kind
+	// of the error, and the time it took
+	RequestFinished(labels Labels, status string, duration time.Duration)
+	// RequestRetried is called before a sent attempt that retries a failed
+	// one, with the errors.Kind of the failure
+	RequestRetried(labels Labels, reason string)
+	// RateLimited is called when an attempt is rejected with 429 Too Many
+	// Requests
+	RateLimited(labels Labels)
+}
+
+// LabelsFor returns the labels of a request. Requests without an operation
+// are labelled with their method.
+func LabelsFor(opts utils.RequestOptions) Labels {
+	if opts.Operation == "" {
+		method := opts.Method
+		if method == "" {
+			method = "GET"
+		}
+		return Labels{Service: "unknown", Operation: method}
+	}
+	service, _, _ := strings.Cut(opts.Operation, ".")
+	return Labels{Service: service, Operation: opts.Operation}
+}
+
+// Status returns the status of an attempt that ended with resp and err
+func Status(resp *utils.Response, err error) string {
+	if resp != nil {
+		return strconv.Itoa(resp.StatusCode)
+	}
+	if err != nil {
+		return errors.Kind(err)
+	}
+	return "ok"
+}
+
+// Observe reports an attempt of the request described by opts to m, calling
+// send to make it
+func Observe(m Metrics, opts utils.RequestOptions, attempt utils.Attempt, send func() (*utils.Response, error)) (*utils.Response, error) {
+	labels := LabelsFor(opts)
+	if attempt.Number > 1 {
+		m.RequestRetried(labels, attempt.RetryReason)
+	}
+
+	m.RequestStarted(labels)
+	start := time.Now()
+	resp, err := send()
+	m.RequestFinished(labels, Status(resp, err), time.Since(start))
+
+	if err != nil && errors.Kind(err) == errors.KindRateLimit {
+		m.RateLimited(labels)
+	}
+	return resp, err
+}
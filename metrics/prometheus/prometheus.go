@@ -0,0 +1,145 @@
+// Package prometheus exports the metrics of a Dokan client to Prometheus.
+//
+// The package is a separate module so that the core SDK does not depend on
+// the Prometheus client:
+//
+//	collector := prometheus.New(prometheus.Options{})
+//	registry.MustRegister(collector)
+//
+//	client, err := dokan.NewClientBuilder().
+//		BaseURL("https://example.com").
+//		BasicAuth("username", "password").
+//		Metrics(collector).
+//		Build()
+package prometheus
+
+import (
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/metrics"
+)
+
+// DefaultNamespace prefixes the names of the metrics
+const DefaultNamespace = "dokan"
+
+// Options configures a Collector
+type Options struct {
+	// Namespace prefixes the names of the metrics. Defaults to
+	// DefaultNamespace.
+	Namespace string
+	// Buckets are the upper bounds, in seconds, of the request duration
+	// histogram. Defaults to prometheus.DefBuckets.
+	Buckets []float64
+	// ConstLabels are added to every metric, e.g. to tell sites apart when
+	// several clients share a registry
+	ConstLabels prom.Labels
+}
+
+// Collector records the metrics of one or more Dokan clients. It implements
+// metrics.Metrics, to be passed to ClientBuilder.Metrics, and
+// prometheus.Collector, to be registered with a Prometheus registry.
+type Collector struct {
+	requests    *prom.CounterVec
+	duration    *prom.HistogramVec
+	retries     *prom.CounterVec
+	rateLimited *prom.CounterVec
+	inFlight    *prom.GaugeVec
+}
+
+var _ metrics.Metrics = (*Collector)(nil)
+var _ prom.Collector = (*Collector)(nil)
+
+// New creates a Collector that exports:
+//
+//   - dokan_requests_total{service, operation, status}
+//   - dokan_request_duration_seconds{service, operation, status}
+//   - dokan_retries_total{service, operation, reason}
+//   - dokan_rate_limited_total{service, operation}
+//   - dokan_requests_in_flight{service, operation}
+//
+// where status is the HTTP status code, or the kind of error of requests
+// that got no response.
+func New(opts Options) *Collector {
+	if opts.Namespace == "" {
+		opts.Namespace = DefaultNamespace
+	}
+	if opts.Buckets == nil {
+		opts.Buckets = prom.DefBuckets
+	}
+
+	return &Collector{
+		requests: prom.NewCounterVec(prom.CounterOpts{
+			Namespace:   opts.Namespace,
+			Name:        "requests_total",
+			Help:        "Number of HTTP requests made to the Dokan API, including retries.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"service", "operation", "status"}),
+		duration: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace:   opts.Namespace,
+			Name:        "request_duration_seconds",
+			Help:        "Duration of HTTP requests made to the Dokan API.",
+			Buckets:     opts.Buckets,
+			ConstLabels: opts.ConstLabels,
+		}, []string{"service", "operation", "status"}),
+		retries: prom.NewCounterVec(prom.CounterOpts{
+			Namespace:   opts.Namespace,
+			Name:        "retries_total",
+			Help:        "Number of retried requests to the Dokan API by the reason for the retry.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"service", "operation", "reason"}),
+		rateLimited: prom.NewCounterVec(prom.CounterOpts{
+			Namespace:   opts.Namespace,
+			Name:        "rate_limited_total",
+			Help:        "Number of requests to the Dokan API rejected with 429 Too Many Requests.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"service", "operation"}),
+		inFlight: prom.NewGaugeVec(prom.GaugeOpts{
+			Namespace:   opts.Namespace,
+			Name:        "requests_in_flight",
+			Help:        "Number of requests to the Dokan API in flight.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"service", "operation"}),
+	}
+}
+
+// RequestStarted implements metrics.Metrics
+func (c *Collector) RequestStarted(labels metrics.Labels) {
+	c.inFlight.WithLabelValues(labels.Service, labels.Operation).Inc()
+}
+
+// RequestFinished implements metrics.Metrics
+func (c *Collector) RequestFinished(labels metrics.Labels, status string, duration time.Duration) {
+	c.inFlight.WithLabelValues(labels.Service, labels.Operation).Dec()
+	c.requests.WithLabelValues(labels.Service, labels.Operation, status).Inc()
+	c.duration.WithLabelValues(labels.Service, labels.Operation, status).Observe(duration.Seconds())
+}
+
+// RequestRetried implements metrics.Metrics
+func (c *Collector) RequestRetried(labels metrics.Labels, reason string) {
+	c.retries.WithLabelValues(labels.Service, labels.Operation, reason).Inc()
+}
+
+// RateLimited implements metrics.Metrics
+func (c *Collector) RateLimited(labels metrics.Labels) {
+	c.rateLimited.WithLabelValues(labels.Service, labels.Operation).Inc()
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prom.Desc) {
+	c.requests.Describe(ch)
+	c.duration.Describe(ch)
+	c.retries.Describe(ch)
+	c.rateLimited.Describe(ch)
+	c.inFlight.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+	c.requests.Collect(ch)
+	c.duration.Collect(ch)
+	c.retries.Collect(ch)
+	c.rateLimited.Collect(ch)
+	c.inFlight.Collect(ch)
+}
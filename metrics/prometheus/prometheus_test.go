@@ -0,0 +1,73 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/client"
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
+)
+
+func TestCollector(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 42, "name": "Mug"}`))
+	}))
+	defer server.Close()
+
+	collector := New(Options{ConstLabels: prom.Labels{"site": "test"}})
+	registry := prom.NewPedanticRegistry()
+	registry.MustRegister(collector)
+
+	c, err := client.NewClientBuilder().
+		BaseURL(server.URL).
+		BasicAuth("testuser", "testpass").
+		RetryPolicy(utils.DefaultRetryPolicy{BaseDelay: time.Millisecond}).
+		Metrics(collector).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, err := c.Products.Get(context.Background(), 42); err != nil {
+		t.Fatalf("Get() returned error: %v", err)
+	}
+
+	expected := `
+# HELP dokan_rate_limited_total Number of requests to the Dokan API rejected with 429 Too Many Requests.
+# TYPE dokan_rate_limited_total counter
+dokan_rate_limited_total{operation="products.get",service="products",site="test"} 1
+# HELP dokan_requests_in_flight Number of requests to the Dokan API in flight.
+# TYPE dokan_requests_in_flight gauge
+dokan_requests_in_flight{operation="products.get",service="products",site="test"} 0
+# HELP dokan_requests_total Number of HTTP requests made to the Dokan API, including retries.
+# TYPE dokan_requests_total counter
+dokan_requests_total{operation="products.get",service="products",site="test",status="200"} 1
+dokan_requests_total{operation="products.get",service="products",site="test",status="429"} 1
+# HELP dokan_retries_total Number of retried requests to the Dokan API by the reason for the retry.
+# TYPE dokan_retries_total counter
+dokan_retries_total{operation="products.get",reason="rate_limit",service="products",site="test"} 1
+`
+	err = testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"dokan_rate_limited_total", "dokan_requests_in_flight", "dokan_requests_total", "dokan_retries_total")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if n := testutil.CollectAndCount(collector, "dokan_request_duration_seconds"); n != 2 {
+		t.Errorf("Expected 2 duration histograms, got %d", n)
+	}
+}
@@ -3,8 +3,11 @@ package orders
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 
+	"github.com/diogenes-moreira/dokan-go-sdk/batch"
+	"github.com/diogenes-moreira/dokan-go-sdk/pagination"
 	"github.com/diogenes-moreira/dokan-go-sdk/types"
 	"github.com/diogenes-moreira/dokan-go-sdk/utils"
 )
@@ -27,8 +30,10 @@ func NewService(client ClientInterface) *Service {
 // Get retrieves a single order by ID
 func (s *Service) Get(ctx context.Context, id int) (*types.Order, error) {
 	opts := utils.RequestOptions{
-		Method: http.MethodGet,
-		Path:   fmt.Sprintf("/wp-json/dokan/v1/orders/%d", id),
+		Method:      http.MethodGet,
+		Path:        fmt.Sprintf("/wp-json/dokan/v1/orders/%d", id),
+		Operation:   "orders.get",
+		ResourceIDs: map[string]int{"order_id": id},
 	}
 	
 	resp, err := s.client.MakeRequest(ctx, opts)
@@ -47,9 +52,10 @@ func (s *Service) Get(ctx context.Context, id int) (*types.Order, error) {
 // List retrieves a list of orders with optional filtering
 func (s *Service) List(ctx context.Context, params *types.OrderListParams) (*OrderListResponse, error) {
 	opts := utils.RequestOptions{
-		Method: http.MethodGet,
-		Path:   "/wp-json/dokan/v1/orders/",
-		Query:  params,
+		Method:    http.MethodGet,
+		Path:      "/wp-json/dokan/v1/orders/",
+		Operation: "orders.list",
+		Query:     params,
 	}
 	
 	resp, err := s.client.MakeRequest(ctx, opts)
@@ -79,12 +85,59 @@ func (s *Service) List(ctx context.Context, params *types.OrderListParams) (*Ord
 	return listResponse, nil
 }
 
+// Iterate returns a cursor over every order matching params, fetching
+// pages on demand. params.Page is ignored; use opts.StartPage instead.
+func (s *Service) Iterate(ctx context.Context, params *types.OrderListParams, opts *pagination.Options) *pagination.Iterator[types.Order] {
+	return pagination.NewIterator(ctx, s.pageFetcher(params), opts)
+}
+
+// All returns an iterator over every order matching params across all pages
+func (s *Service) All(ctx context.Context, params *types.OrderListParams, opts *pagination.Options) iter.Seq2[types.Order, error] {
+	return pagination.All(ctx, s.pageFetcher(params), opts)
+}
+
+// ListAllConcurrent fetches every order matching params. The first page is
+// fetched to learn the page count, then the remaining pages are fetched in
+// parallel with up to opts.Concurrency workers. Results keep page order.
+func (s *Service) ListAllConcurrent(ctx context.Context, params *types.OrderListParams, opts *pagination.Options) ([]types.Order, error) {
+	orders, err := pagination.FetchAll(ctx, s.pageFetcher(params), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list all orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// pageFetcher returns a fetcher that lists a single page of orders
+func (s *Service) pageFetcher(params *types.OrderListParams) pagination.Fetcher[types.Order] {
+	return func(ctx context.Context, page int) (*pagination.Page[types.Order], error) {
+		var pageParams types.OrderListParams
+		if params != nil {
+			pageParams = *params
+		}
+		pageParams.Page = page
+
+		resp, err := s.List(ctx, &pageParams)
+		if err != nil {
+			return nil, err
+		}
+
+		return &pagination.Page[types.Order]{
+			Items:      resp.Orders,
+			TotalItems: resp.TotalItems,
+			TotalPages: resp.TotalPages,
+		}, nil
+	}
+}
+
 // Update updates an existing order
 func (s *Service) Update(ctx context.Context, id int, order *OrderUpdate) (*types.Order, error) {
 	opts := utils.RequestOptions{
-		Method: http.MethodPut,
-		Path:   fmt.Sprintf("/wp-json/dokan/v1/orders/%d", id),
-		Body:   order,
+		Method:      http.MethodPut,
+		Path:        fmt.Sprintf("/wp-json/dokan/v1/orders/%d", id),
+		Operation:   "orders.update",
+		ResourceIDs: map[string]int{"order_id": id},
+		Body:        order,
 	}
 	
 	resp, err := s.client.MakeRequest(ctx, opts)
@@ -100,11 +153,25 @@ func (s *Service) Update(ctx context.Context, id int, order *OrderUpdate) (*type
 	return &updatedOrder, nil
 }
 
+// Batch updates and deletes orders. Each update must set OrderUpdate.ID.
+// Orders cannot be created in batch, so req.Create must be empty. Inputs
+// larger than the server limit are split into several requests; see
+// batch.Run.
+func (s *Service) Batch(ctx context.Context, req *types.BatchRequest[OrderUpdate], opts *batch.Options) (*batch.Result[types.Order], error) {
+	if req != nil && len(req.Create) > 0 {
+		return nil, fmt.Errorf("orders batch does not support create, got %d creates", len(req.Create))
+	}
+	
+	send := batch.NewSender[OrderUpdate](s.client, "/wp-json/dokan/v1/orders/batch", "orders.batch")
+	return batch.Run[OrderUpdate, types.Order](ctx, send, req, opts)
+}
+
 // GetSummary retrieves a summary of orders
 func (s *Service) GetSummary(ctx context.Context) (*OrderSummary, error) {
 	opts := utils.RequestOptions{
-		Method: http.MethodGet,
-		Path:   "/wp-json/dokan/v1/orders/summary",
+		Method:    http.MethodGet,
+		Path:      "/wp-json/dokan/v1/orders/summary",
+		Operation: "orders.summary",
 	}
 	
 	resp, err := s.client.MakeRequest(ctx, opts)
@@ -128,6 +195,7 @@ type OrderListResponse struct {
 
 // OrderUpdate represents fields that can be updated in an order
 type OrderUpdate struct {
+	ID           int               `json:"id,omitempty"` // required for batch updates
 	Status       *types.OrderStatus `json:"status,omitempty"`
 	CustomerNote *string           `json:"customer_note,omitempty"`
 	Billing      *types.Address    `json:"billing,omitempty"`
@@ -141,9 +209,9 @@ type OrderUpdate struct {
 
 // OrderSummary represents a summary of orders
 type OrderSummary struct {
-	Total      int                        `json:"total"`
-	Totals     map[string]int            `json:"totals"`
-	StatusCounts map[types.OrderStatus]int `json:"status_counts"`
+	Total      types.FlexInt                        `json:"total"`
+	Totals     map[string]types.FlexInt            `json:"totals"`
+	StatusCounts map[types.OrderStatus]types.FlexInt `json:"status_counts"`
 }
 
 // extractIntHeader extracts an integer value from HTTP headers
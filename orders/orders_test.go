@@ -0,0 +1,42 @@
+package orders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/types"
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
+)
+
+// fakeClient records requests and answers with an empty batch response
+type fakeClient struct {
+	requests []utils.RequestOptions
+}
+
+func (c *fakeClient) MakeRequest(ctx context.Context, opts utils.RequestOptions) (*utils.Response, error) {
+	c.requests = append(c.requests, opts)
+	return &utils.Response{StatusCode: 200, Body: []byte(`{}`)}, nil
+}
+
+func TestService_Batch_RejectsCreate(t *testing.T) {
+	client := &fakeClient{}
+	service := NewService(client)
+
+	_, err := service.Batch(context.Background(), &types.BatchRequest[OrderUpdate]{
+		Create: []OrderUpdate{{}},
+		Delete: []int{1},
+	}, nil)
+	if err == nil {
+		t.Fatal("Batch() should return error for creates")
+	}
+	if len(client.requests) != 0 {
+		t.Errorf("Expected no request, got %d", len(client.requests))
+	}
+
+	if _, err := service.Batch(context.Background(), &types.BatchRequest[OrderUpdate]{Delete: []int{1}}, nil); err != nil {
+		t.Fatalf("Batch() returned error: %v", err)
+	}
+	if len(client.requests) != 1 || client.requests[0].Path != "/wp-json/dokan/v1/orders/batch" {
+		t.Errorf("Unexpected requests: %+v", client.requests)
+	}
+}
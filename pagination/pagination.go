@@ -0,0 +1,316 @@
+// Package pagination provides iterators that walk every page of a Dokan list
+// endpoint, using the X-WP-TotalPages header reported by WordPress.
+package pagination
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// DefaultConcurrency is the number of pages fetched in parallel by FetchAll
+// when Options.Concurrency is not set
+const DefaultConcurrency = 4
+
+// Page represents a single page returned by a list endpoint
+type Page[T any] struct {
+	Items      []T
+	TotalItems int
+	TotalPages int
+}
+
+// Fetcher retrieves a single page of results. Pages are numbered from 1.
+type Fetcher[T any] func(ctx context.Context, page int) (*Page[T], error)
+
+// Options controls how an iterator walks the pages of a list endpoint
+type Options struct {
+	// StartPage is the first page to fetch. Defaults to 1.
+	StartPage int
+
+	// MaxItems caps the total number of items returned. Zero means no limit.
+	MaxItems int
+
+	// Concurrency is the number of pages fetched in parallel by FetchAll.
+	// Iterators always fetch one page at a time.
+	Concurrency int
+}
+
+// Iterator is a cursor over every item of a paginated list endpoint.
+// Pages are fetched lazily as the cursor advances.
+//
+//	it := client.Products.Iterate(ctx, params, nil)
+//	for it.Next() {
+//		product := it.Item()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch Fetcher[T]
+	opts  Options
+
+	page       int
+	totalPages int
+	buffer     []T
+	index      int
+	item       T
+	count      int
+	lastPage   bool
+	done       bool
+	err        error
+}
+
+// NewIterator creates a new iterator that uses fetch to retrieve each page
+func NewIterator[T any](ctx context.Context, fetch Fetcher[T], opts *Options) *Iterator[T] {
+	it := &Iterator[T]{
+		ctx:   ctx,
+		fetch: fetch,
+	}
+	if opts != nil {
+		it.opts = *opts
+	}
+
+	it.page = it.opts.StartPage
+	if it.page < 1 {
+		it.page = 1
+	}
+
+	return it
+}
+
+// Next advances the iterator to the next item, fetching a new page when the
+// current one is exhausted. It returns false when there are no more items,
+// the item limit is reached, the context is cancelled or a request fails.
+func (it *Iterator[T]) Next() bool {
+	if it.done {
+		return false
+	}
+
+	if it.opts.MaxItems > 0 && it.count >= it.opts.MaxItems {
+		return it.stop(nil)
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		return it.stop(err)
+	}
+
+	for it.index >= len(it.buffer) {
+		if it.lastPage {
+			return it.stop(nil)
+		}
+
+		page, err := it.fetch(it.ctx, it.page)
+		if err != nil {
+			return it.stop(err)
+		}
+		if page == nil || len(page.Items) == 0 {
+			return it.stop(nil)
+		}
+
+		it.buffer = page.Items
+		it.index = 0
+		it.totalPages = page.TotalPages
+
+		// Without a total page count we keep going until an empty page
+		if page.TotalPages > 0 && it.page >= page.TotalPages {
+			it.lastPage = true
+		}
+		it.page++
+	}
+
+	it.item = it.buffer[it.index]
+	it.index++
+	it.count++
+	return true
+}
+
+// Item returns the current item. It is only valid after a call to Next
+// that returned true.
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// TotalPages returns the total number of pages reported by the server,
+// or zero if no page has been fetched yet.
+func (it *Iterator[T]) TotalPages() int {
+	return it.totalPages
+}
+
+// stop ends the iteration, recording err, and returns false
+func (it *Iterator[T]) stop(err error) bool {
+	var zero T
+	it.done = true
+	it.err = err
+	it.item = zero
+	it.buffer = nil
+	return false
+}
+
+// All returns a range-over-func iterator over every item. If a page fails to
+// load or the context is cancelled, the error is yielded as the final pair.
+//
+//	for product, err := range client.Products.All(ctx, params, nil) {
+//		if err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+func All[T any](ctx context.Context, fetch Fetcher[T], opts *Options) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		it := NewIterator(ctx, fetch, opts)
+		for it.Next() {
+			if !yield(it.Item(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// FetchAll fetches the first page to learn the page count, then fetches the
+// remaining pages with a bounded pool of workers. Items are returned in page
+// order regardless of the order in which the pages complete. If the server
+// does not report a page count, the pages are fetched sequentially.
+func FetchAll[T any](ctx context.Context, fetch Fetcher[T], opts *Options) ([]T, error) {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.StartPage < 1 {
+		o.StartPage = 1
+	}
+	if o.Concurrency < 1 {
+		o.Concurrency = DefaultConcurrency
+	}
+
+	first, err := fetch(ctx, o.StartPage)
+	if err != nil {
+		return nil, err
+	}
+	if first == nil || len(first.Items) == 0 {
+		return nil, nil
+	}
+
+	if first.TotalPages == 0 {
+		return fetchSequential(ctx, fetch, first, o)
+	}
+
+	lastPage := first.TotalPages
+	if o.MaxItems > 0 {
+		needed := o.StartPage + (o.MaxItems+len(first.Items)-1)/len(first.Items) - 1
+		if needed < lastPage {
+			lastPage = needed
+		}
+	}
+	if lastPage < o.StartPage {
+		lastPage = o.StartPage
+	}
+
+	pages := make([][]T, lastPage-o.StartPage+1)
+	pages[0] = first.Items
+
+	if len(pages) > 1 {
+		if err := fetchPages(ctx, fetch, o.StartPage+1, lastPage, o.Concurrency, pages[1:]); err != nil {
+			return nil, err
+		}
+	}
+
+	var items []T
+	for _, page := range pages {
+		items = append(items, page...)
+	}
+
+	if o.MaxItems > 0 && len(items) > o.MaxItems {
+		items = items[:o.MaxItems]
+	}
+
+	return items, nil
+}
+
+// fetchPages fetches pages from..to with up to concurrency workers, storing
+// each page's items in results. The first error cancels the remaining work.
+func fetchPages[T any](ctx context.Context, fetch Fetcher[T], from, to, concurrency int, results [][]T) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pageNumbers := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	for i := 0; i < concurrency && i <= to-from; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range pageNumbers {
+				result, err := fetch(ctx, page)
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				if result != nil {
+					results[page-from] = result.Items
+				}
+			}
+		}()
+	}
+
+	func() {
+		defer close(pageNumbers)
+		for page := from; page <= to; page++ {
+			select {
+			case pageNumbers <- page:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// fetchSequential walks the remaining pages one at a time, starting after
+// the already fetched first page
+func fetchSequential[T any](ctx context.Context, fetch Fetcher[T], first *Page[T], o Options) ([]T, error) {
+	items := append([]T(nil), first.Items...)
+	for page := o.StartPage + 1; o.MaxItems <= 0 || len(items) < o.MaxItems; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		next, err := fetch(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		if next == nil || len(next.Items) == 0 {
+			break
+		}
+		items = append(items, next.Items...)
+	}
+
+	if o.MaxItems > 0 && len(items) > o.MaxItems {
+		items = items[:o.MaxItems]
+	}
+
+	return items, nil
+}
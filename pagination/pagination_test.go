@@ -0,0 +1,311 @@
+package pagination
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pagedFetcher returns a fetcher over items split into pages of perPage,
+// recording every page that was requested.
+func pagedFetcher(items []int, perPage int, requested *[]int) Fetcher[int] {
+	totalPages := (len(items) + perPage - 1) / perPage
+	return func(ctx context.Context, page int) (*Page[int], error) {
+		*requested = append(*requested, page)
+		start := (page - 1) * perPage
+		if start >= len(items) {
+			return &Page[int]{TotalItems: len(items), TotalPages: totalPages}, nil
+		}
+		end := start + perPage
+		if end > len(items) {
+			end = len(items)
+		}
+		return &Page[int]{Items: items[start:end], TotalItems: len(items), TotalPages: totalPages}, nil
+	}
+}
+
+func sequence(n int) []int {
+	items := make([]int, n)
+	for i := range items {
+		items[i] = i + 1
+	}
+	return items
+}
+
+func TestIterator_WalksAllPages(t *testing.T) {
+	var requested []int
+	it := NewIterator(context.Background(), pagedFetcher(sequence(25), 10, &requested), nil)
+
+	var got []int
+	for it.Next() {
+		got = append(got, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() returned error: %v", err)
+	}
+
+	if len(got) != 25 {
+		t.Fatalf("Expected 25 items, got %d", len(got))
+	}
+	for i, v := range got {
+		if v != i+1 {
+			t.Errorf("Expected item %d to be %d, got %d", i, i+1, v)
+		}
+	}
+
+	// The last page is known from TotalPages, so no extra request is made
+	if len(requested) != 3 {
+		t.Errorf("Expected 3 page requests, got %v", requested)
+	}
+	if it.TotalPages() != 3 {
+		t.Errorf("Expected TotalPages 3, got %d", it.TotalPages())
+	}
+}
+
+func TestIterator_MaxItems(t *testing.T) {
+	var requested []int
+	it := NewIterator(context.Background(), pagedFetcher(sequence(25), 10, &requested), &Options{MaxItems: 12})
+
+	count := 0
+	for it.Next() {
+		count++
+	}
+
+	if count != 12 {
+		t.Errorf("Expected 12 items, got %d", count)
+	}
+	if len(requested) != 2 {
+		t.Errorf("Expected 2 page requests, got %v", requested)
+	}
+}
+
+func TestIterator_StartPage(t *testing.T) {
+	var requested []int
+	it := NewIterator(context.Background(), pagedFetcher(sequence(25), 10, &requested), &Options{StartPage: 3})
+
+	var got []int
+	for it.Next() {
+		got = append(got, it.Item())
+	}
+
+	if len(got) != 5 || got[0] != 21 {
+		t.Errorf("Expected items 21..25, got %v", got)
+	}
+}
+
+func TestIterator_WithoutTotalPages(t *testing.T) {
+	calls := 0
+	fetch := func(ctx context.Context, page int) (*Page[int], error) {
+		calls++
+		if page > 2 {
+			return &Page[int]{}, nil
+		}
+		return &Page[int]{Items: []int{page}}, nil
+	}
+
+	it := NewIterator(context.Background(), fetch, nil)
+	count := 0
+	for it.Next() {
+		count++
+	}
+
+	if count != 2 {
+		t.Errorf("Expected 2 items, got %d", count)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 fetches ending with an empty page, got %d", calls)
+	}
+}
+
+func TestIterator_FetchError(t *testing.T) {
+	fetch := func(ctx context.Context, page int) (*Page[int], error) {
+		if page == 2 {
+			return nil, fmt.Errorf("boom")
+		}
+		return &Page[int]{Items: []int{1, 2}, TotalPages: 3}, nil
+	}
+
+	it := NewIterator(context.Background(), fetch, nil)
+	count := 0
+	for it.Next() {
+		count++
+	}
+
+	if count != 2 {
+		t.Errorf("Expected 2 items before the error, got %d", count)
+	}
+	if it.Err() == nil {
+		t.Error("Err() should return the fetch error")
+	}
+}
+
+func TestIterator_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var requested []int
+	it := NewIterator(ctx, pagedFetcher(sequence(25), 10, &requested), nil)
+
+	if !it.Next() {
+		t.Fatal("Next() should return true for the first item")
+	}
+	cancel()
+
+	if it.Next() {
+		t.Error("Next() should return false after the context is cancelled")
+	}
+	if it.Err() != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", it.Err())
+	}
+}
+
+func TestAll(t *testing.T) {
+	var requested []int
+	sum := 0
+	for v, err := range All(context.Background(), pagedFetcher(sequence(10), 3, &requested), nil) {
+		if err != nil {
+			t.Fatalf("All() yielded error: %v", err)
+		}
+		sum += v
+	}
+
+	if sum != 55 {
+		t.Errorf("Expected sum 55, got %d", sum)
+	}
+}
+
+func TestAll_YieldsError(t *testing.T) {
+	fetch := func(ctx context.Context, page int) (*Page[int], error) {
+		return nil, fmt.Errorf("boom")
+	}
+
+	var errs int
+	for _, err := range All(context.Background(), fetch, nil) {
+		if err != nil {
+			errs++
+		}
+	}
+
+	if errs != 1 {
+		t.Errorf("Expected a single error, got %d", errs)
+	}
+}
+
+func TestAll_Break(t *testing.T) {
+	var requested []int
+	count := 0
+	for range All(context.Background(), pagedFetcher(sequence(25), 10, &requested), nil) {
+		count++
+		if count == 5 {
+			break
+		}
+	}
+
+	if len(requested) != 1 {
+		t.Errorf("Expected a single page request, got %v", requested)
+	}
+}
+
+func TestFetchAll_KeepsPageOrder(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		inFlight  int
+		maxFlight int
+	)
+	items := sequence(95)
+	fetch := func(ctx context.Context, page int) (*Page[int], error) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxFlight {
+			maxFlight = inFlight
+		}
+		mu.Unlock()
+
+		// Later pages finish first to exercise reordering
+		time.Sleep(time.Duration(10-page) * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		start := (page - 1) * 10
+		end := start + 10
+		if end > len(items) {
+			end = len(items)
+		}
+		return &Page[int]{Items: items[start:end], TotalItems: len(items), TotalPages: 10}, nil
+	}
+
+	got, err := FetchAll(context.Background(), fetch, &Options{Concurrency: 3})
+	if err != nil {
+		t.Fatalf("FetchAll() returned error: %v", err)
+	}
+
+	if len(got) != 95 {
+		t.Fatalf("Expected 95 items, got %d", len(got))
+	}
+	for i, v := range got {
+		if v != i+1 {
+			t.Fatalf("Expected item %d to be %d, got %d", i, i+1, v)
+		}
+	}
+	if maxFlight > 3 {
+		t.Errorf("Expected at most 3 concurrent fetches, got %d", maxFlight)
+	}
+}
+
+func TestFetchAll_MaxItems(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		requested []int
+	)
+	inner := pagedFetcher(sequence(100), 10, &requested)
+	fetch := func(ctx context.Context, page int) (*Page[int], error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return inner(ctx, page)
+	}
+
+	got, err := FetchAll(context.Background(), fetch, &Options{MaxItems: 25})
+	if err != nil {
+		t.Fatalf("FetchAll() returned error: %v", err)
+	}
+
+	if len(got) != 25 {
+		t.Errorf("Expected 25 items, got %d", len(got))
+	}
+	if len(requested) != 3 {
+		t.Errorf("Expected 3 page requests, got %v", requested)
+	}
+}
+
+func TestFetchAll_Error(t *testing.T) {
+	fetch := func(ctx context.Context, page int) (*Page[int], error) {
+		if page == 4 {
+			return nil, fmt.Errorf("boom")
+		}
+		return &Page[int]{Items: []int{page}, TotalPages: 8}, nil
+	}
+
+	if _, err := FetchAll(context.Background(), fetch, nil); err == nil {
+		t.Error("FetchAll() should return the page error")
+	}
+}
+
+func TestFetchAll_WithoutTotalPages(t *testing.T) {
+	fetch := func(ctx context.Context, page int) (*Page[int], error) {
+		if page > 3 {
+			return &Page[int]{}, nil
+		}
+		return &Page[int]{Items: []int{page}}, nil
+	}
+
+	got, err := FetchAll(context.Background(), fetch, nil)
+	if err != nil {
+		t.Fatalf("FetchAll() returned error: %v", err)
+	}
+
+	if len(got) != 3 {
+		t.Errorf("Expected 3 items, got %v", got)
+	}
+}
@@ -2,9 +2,13 @@ package products
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 
+	"github.com/diogenes-moreira/dokan-go-sdk/batch"
+	"github.com/diogenes-moreira/dokan-go-sdk/pagination"
 	"github.com/diogenes-moreira/dokan-go-sdk/types"
 	"github.com/diogenes-moreira/dokan-go-sdk/utils"
 )
@@ -12,6 +16,9 @@ import (
 // Service provides methods for interacting with the Dokan Products API
 type Service struct {
 	client ClientInterface
+
+	// Variations provides access to the variations of variable products
+	Variations *VariationService
 }
 
 // ClientInterface defines the interface for making HTTP requests
@@ -21,15 +28,32 @@ type ClientInterface interface {
 
 // NewService creates a new products service
 func NewService(client ClientInterface) *Service {
-	return &Service{client: client}
+	return &Service{
+		client:     client,
+		Variations: NewVariationService(client),
+	}
 }
 
-// Create creates a new product in the Dokan marketplace
+// IdempotencyKeyMeta is the meta_data key of a client-supplied idempotency
+// key. Before a create is retried after a failure that may have reached the
+// server, the product is looked up by SKU, or by name when it has no SKU, and
+// a match carrying the same key is returned instead of creating a duplicate.
+//
+// A product found by SKU without the key cannot be told apart from one that
+// already existed, so the create is not retried and the original error is
+// returned. Products with neither a key nor a SKU are not retried.
+const IdempotencyKeyMeta = "_dokan_sdk_idempotency_key"
+
+// Create creates a new product in the Dokan marketplace. Ambiguous failures
+// are only retried for products that have a SKU or an IdempotencyKeyMeta
+// entry; see IdempotencyKeyMeta.
 func (s *Service) Create(ctx context.Context, product *types.Product) (*types.Product, error) {
 	opts := utils.RequestOptions{
-		Method: http.MethodPost,
-		Path:   "/wp-json/dokan/v1/products/",
-		Body:   product,
+		Method:    http.MethodPost,
+		Path:      "/wp-json/dokan/v1/products/",
+		Operation: "products.create",
+		Body:      product,
+		Dedupe:    s.dedupeCreate(product),
 	}
 	
 	resp, err := s.client.MakeRequest(ctx, opts)
@@ -45,11 +69,72 @@ func (s *Service) Create(ctx context.Context, product *types.Product) (*types.Pr
 	return &createdProduct, nil
 }
 
+// dedupeCreate returns a function that finds the product created by a
+// previous attempt to create product, or nil if product cannot be identified
+func (s *Service) dedupeCreate(product *types.Product) func(ctx context.Context) (*utils.Response, error) {
+	if product == nil {
+		return nil
+	}
+	
+	key := idempotencyKey(product)
+	if product.SKU == "" && (key == "" || product.Name == "") {
+		return nil
+	}
+	
+	return func(ctx context.Context) (*utils.Response, error) {
+		// Search server-side by SKU, which is unique, or else by name
+		params := &types.ProductListParams{SKU: product.SKU}
+		if product.SKU == "" {
+			params.Search = product.Name
+		}
+		
+		for existing, err := range s.All(ctx, params, nil) {
+			if err != nil {
+				return nil, err
+			}
+			if product.SKU != "" && existing.SKU != product.SKU {
+				continue
+			}
+			
+			if key != "" && idempotencyKey(&existing) == key {
+				body, err := json.Marshal(existing)
+				if err != nil {
+					return nil, err
+				}
+				return &utils.Response{StatusCode: http.StatusCreated, Body: body}, nil
+			}
+			
+			// The SKU is taken, by a product this call may or may not have
+			// created
+			if product.SKU != "" {
+				return nil, fmt.Errorf("product with SKU %q already exists", product.SKU)
+			}
+		}
+		
+		return nil, nil
+	}
+}
+
+// idempotencyKey returns the IdempotencyKeyMeta value of product, or "" if
+// it has none
+func idempotencyKey(product *types.Product) string {
+	for _, meta := range product.MetaData {
+		if meta.Key == IdempotencyKeyMeta {
+			if key, ok := meta.Value.(string); ok {
+				return key
+			}
+		}
+	}
+	return ""
+}
+
 // Get retrieves a single product by ID
 func (s *Service) Get(ctx context.Context, id int) (*types.Product, error) {
 	opts := utils.RequestOptions{
-		Method: http.MethodGet,
-		Path:   fmt.Sprintf("/wp-json/dokan/v1/products/%d", id),
+		Method:      http.MethodGet,
+		Path:        fmt.Sprintf("/wp-json/dokan/v1/products/%d", id),
+		Operation:   "products.get",
+		ResourceIDs: map[string]int{"product_id": id},
 	}
 	
 	resp, err := s.client.MakeRequest(ctx, opts)
@@ -68,9 +153,10 @@ func (s *Service) Get(ctx context.Context, id int) (*types.Product, error) {
 // List retrieves a list of products with optional filtering
 func (s *Service) List(ctx context.Context, params *types.ProductListParams) (*ProductListResponse, error) {
 	opts := utils.RequestOptions{
-		Method: http.MethodGet,
-		Path:   "/wp-json/dokan/v1/products/",
-		Query:  params,
+		Method:    http.MethodGet,
+		Path:      "/wp-json/dokan/v1/products/",
+		Operation: "products.list",
+		Query:     params,
 	}
 	
 	resp, err := s.client.MakeRequest(ctx, opts)
@@ -100,12 +186,59 @@ func (s *Service) List(ctx context.Context, params *types.ProductListParams) (*P
 	return listResponse, nil
 }
 
+// Iterate returns a cursor over every product matching params, fetching
+// pages on demand. params.Page is ignored; use opts.StartPage instead.
+func (s *Service) Iterate(ctx context.Context, params *types.ProductListParams, opts *pagination.Options) *pagination.Iterator[types.Product] {
+	return pagination.NewIterator(ctx, s.pageFetcher(params), opts)
+}
+
+// All returns an iterator over every product matching params across all pages
+func (s *Service) All(ctx context.Context, params *types.ProductListParams, opts *pagination.Options) iter.Seq2[types.Product, error] {
+	return pagination.All(ctx, s.pageFetcher(params), opts)
+}
+
+// ListAllConcurrent fetches every product matching params. The first page is
+// fetched to learn the page count, then the remaining pages are fetched in
+// parallel with up to opts.Concurrency workers. Results keep page order.
+func (s *Service) ListAllConcurrent(ctx context.Context, params *types.ProductListParams, opts *pagination.Options) ([]types.Product, error) {
+	products, err := pagination.FetchAll(ctx, s.pageFetcher(params), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list all products: %w", err)
+	}
+
+	return products, nil
+}
+
+// pageFetcher returns a fetcher that lists a single page of products
+func (s *Service) pageFetcher(params *types.ProductListParams) pagination.Fetcher[types.Product] {
+	return func(ctx context.Context, page int) (*pagination.Page[types.Product], error) {
+		var pageParams types.ProductListParams
+		if params != nil {
+			pageParams = *params
+		}
+		pageParams.Page = page
+
+		resp, err := s.List(ctx, &pageParams)
+		if err != nil {
+			return nil, err
+		}
+
+		return &pagination.Page[types.Product]{
+			Items:      resp.Products,
+			TotalItems: resp.TotalItems,
+			TotalPages: resp.TotalPages,
+		}, nil
+	}
+}
+
 // Update updates an existing product
 func (s *Service) Update(ctx context.Context, id int, product *types.Product) (*types.Product, error) {
 	opts := utils.RequestOptions{
-		Method: http.MethodPut,
-		Path:   fmt.Sprintf("/wp-json/dokan/v1/products/%d", id),
-		Body:   product,
+		Method:      http.MethodPut,
+		Path:        fmt.Sprintf("/wp-json/dokan/v1/products/%d", id),
+		Operation:   "products.update",
+		ResourceIDs: map[string]int{"product_id": id},
+		Body:        product,
 	}
 	
 	resp, err := s.client.MakeRequest(ctx, opts)
@@ -124,8 +257,10 @@ func (s *Service) Update(ctx context.Context, id int, product *types.Product) (*
 // Delete deletes a product by ID
 func (s *Service) Delete(ctx context.Context, id int) error {
 	opts := utils.RequestOptions{
-		Method: http.MethodDelete,
-		Path:   fmt.Sprintf("/wp-json/dokan/v1/products/%d", id),
+		Method:      http.MethodDelete,
+		Path:        fmt.Sprintf("/wp-json/dokan/v1/products/%d", id),
+		Operation:   "products.delete",
+		ResourceIDs: map[string]int{"product_id": id},
 	}
 	
 	_, err := s.client.MakeRequest(ctx, opts)
@@ -136,11 +271,18 @@ func (s *Service) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
+// Batch creates, updates and deletes products. Inputs larger than the
+// server limit are split into several requests; see batch.Run.
+func (s *Service) Batch(ctx context.Context, req *types.BatchRequest[types.Product], opts *batch.Options) (*batch.Result[types.Product], error) {
+	return batch.Run[types.Product, types.Product](ctx, batch.NewSender[types.Product](s.client, "/wp-json/dokan/v1/products/batch", "products.batch"), req, opts)
+}
+
 // GetSummary retrieves a summary of products
 func (s *Service) GetSummary(ctx context.Context) (*ProductSummary, error) {
 	opts := utils.RequestOptions{
-		Method: http.MethodGet,
-		Path:   "/wp-json/dokan/v1/products/summary",
+		Method:    http.MethodGet,
+		Path:      "/wp-json/dokan/v1/products/summary",
+		Operation: "products.summary",
 	}
 	
 	resp, err := s.client.MakeRequest(ctx, opts)
@@ -164,11 +306,11 @@ type ProductListResponse struct {
 
 // ProductSummary represents a summary of products
 type ProductSummary struct {
-	Total     int `json:"total"`
-	Published int `json:"published"`
-	Draft     int `json:"draft"`
-	Pending   int `json:"pending"`
-	Featured  int `json:"featured"`
+	Total     types.FlexInt `json:"total"`
+	Published types.FlexInt `json:"published"`
+	Draft     types.FlexInt `json:"draft"`
+	Pending   types.FlexInt `json:"pending"`
+	Featured  types.FlexInt `json:"featured"`
 }
 
 // extractIntHeader extracts an integer value from HTTP headers
@@ -0,0 +1,89 @@
+package products
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/types"
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
+)
+
+// singlePage returns a response holding body as the only page of results
+func singlePage(body string) *utils.Response {
+	headers := http.Header{}
+	headers.Set("X-WP-TotalPages", "1")
+	return &utils.Response{StatusCode: http.StatusOK, Headers: headers, Body: []byte(body)}
+}
+
+func TestService_CreateDedupe(t *testing.T) {
+	client := &fakeClient{response: singlePage(`[{"id": 3, "sku": "OTHER"}, {"id": 4, "sku": "MUG-1"}]`)}
+	service := NewService(client)
+
+	dedupe := service.dedupeCreate(&types.Product{Name: "Mug", SKU: "MUG-1"})
+	if dedupe == nil {
+		t.Fatal("Expected a dedupe function for a product with a SKU")
+	}
+
+	// Without an idempotency key, the product may have existed before
+	if resp, err := dedupe(context.Background()); err == nil {
+		t.Errorf("Expected an error for a SKU match without the key, got %v", resp)
+	}
+	if params := client.requests[0].Query.(*types.ProductListParams); params.SKU != "MUG-1" {
+		t.Errorf("Expected lookup by SKU, got %+v", params)
+	}
+
+	client.response = singlePage(`[{"id": 3, "sku": "OTHER"}]`)
+	if resp, err := dedupe(context.Background()); err != nil || resp != nil {
+		t.Errorf("Expected no match when the SKU is free, got %v, %v", resp, err)
+	}
+
+	client.response = singlePage(`[{"id": 4, "sku": "MUG-1", "meta_data": [{"key": "_dokan_sdk_idempotency_key", "value": "abc"}]}]`)
+	product := &types.Product{
+		Name:     "Mug",
+		SKU:      "MUG-1",
+		MetaData: []types.MetaData{{Key: IdempotencyKeyMeta, Value: "abc"}},
+	}
+	resp, err := service.dedupeCreate(product)(context.Background())
+	if err != nil || resp == nil {
+		t.Fatalf("Expected a match for the SKU and key, got %v, %v", resp, err)
+	}
+
+	var found types.Product
+	if err := utils.ParseJSON(resp.Body, &found); err != nil {
+		t.Fatalf("Failed to parse deduplicated product: %v", err)
+	}
+	if found.ID != 4 {
+		t.Errorf("Expected product 4, got %d", found.ID)
+	}
+}
+
+func TestService_CreateDedupe_IdempotencyKey(t *testing.T) {
+	client := &fakeClient{response: singlePage(`[{"id": 7, "name": "Mug"}, {"id": 8, "name": "Mug", "meta_data": [{"key": "_dokan_sdk_idempotency_key", "value": "abc"}]}]`)}
+	service := NewService(client)
+
+	if service.dedupeCreate(&types.Product{Name: "Mug"}) != nil {
+		t.Error("Expected no dedupe function without a SKU or idempotency key")
+	}
+
+	product := &types.Product{
+		Name:     "Mug",
+		MetaData: []types.MetaData{{Key: IdempotencyKeyMeta, Value: "xyz"}},
+	}
+	resp, err := service.dedupeCreate(product)(context.Background())
+	if err != nil {
+		t.Fatalf("dedupe() returned error: %v", err)
+	}
+	if resp != nil {
+		t.Errorf("Expected no match for a different key, got %s", resp.Body)
+	}
+	if params := client.requests[0].Query.(*types.ProductListParams); params.Search != "Mug" {
+		t.Errorf("Expected lookup by name, got %+v", params)
+	}
+
+	product.MetaData[0].Value = "abc"
+	resp, err = service.dedupeCreate(product)(context.Background())
+	if err != nil || resp == nil {
+		t.Fatalf("Expected a match for the same key, got %v, %v", resp, err)
+	}
+}
@@ -0,0 +1,243 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/batch"
+	"github.com/diogenes-moreira/dokan-go-sdk/pagination"
+	"github.com/diogenes-moreira/dokan-go-sdk/types"
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
+)
+
+// VariationService provides methods for interacting with the variations of
+// variable products
+type VariationService struct {
+	client ClientInterface
+}
+
+// NewVariationService creates a new product variations service
+func NewVariationService(client ClientInterface) *VariationService {
+	return &VariationService{client: client}
+}
+
+// List retrieves a list of variations of a product
+func (s *VariationService) List(ctx context.Context, productID int, params *types.VariationListParams) (*VariationListResponse, error) {
+	opts := utils.RequestOptions{
+		Method:      http.MethodGet,
+		Path:        fmt.Sprintf("/wp-json/dokan/v1/products/%d/variations", productID),
+		Operation:   "variations.list",
+		ResourceIDs: map[string]int{"product_id": productID},
+		Query:       params,
+	}
+
+	resp, err := s.client.MakeRequest(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product variations: %w", err)
+	}
+
+	var variations []types.ProductVariation
+	if err := utils.ParseJSON(resp.Body, &variations); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// Extract pagination info from headers
+	listResponse := &VariationListResponse{
+		Variations: variations,
+		ProductID:  productID,
+		ListResponse: types.ListResponse{
+			TotalItems: extractIntHeader(resp.Headers, "X-WP-Total"),
+			TotalPages: extractIntHeader(resp.Headers, "X-WP-TotalPages"),
+		},
+	}
+
+	if params != nil {
+		listResponse.Page = params.Page
+		listResponse.PerPage = params.PerPage
+	}
+
+	return listResponse, nil
+}
+
+// Iterate returns a cursor over every variation of a product, fetching
+// pages on demand. params.Page is ignored; use opts.StartPage instead.
+func (s *VariationService) Iterate(ctx context.Context, productID int, params *types.VariationListParams, opts *pagination.Options) *pagination.Iterator[types.ProductVariation] {
+	return pagination.NewIterator(ctx, s.pageFetcher(productID, params), opts)
+}
+
+// All returns an iterator over every variation of a product across all pages
+func (s *VariationService) All(ctx context.Context, productID int, params *types.VariationListParams, opts *pagination.Options) iter.Seq2[types.ProductVariation, error] {
+	return pagination.All(ctx, s.pageFetcher(productID, params), opts)
+}
+
+// pageFetcher returns a fetcher that lists a single page of variations
+func (s *VariationService) pageFetcher(productID int, params *types.VariationListParams) pagination.Fetcher[types.ProductVariation] {
+	return func(ctx context.Context, page int) (*pagination.Page[types.ProductVariation], error) {
+		var pageParams types.VariationListParams
+		if params != nil {
+			pageParams = *params
+		}
+		pageParams.Page = page
+
+		resp, err := s.List(ctx, productID, &pageParams)
+		if err != nil {
+			return nil, err
+		}
+
+		return &pagination.Page[types.ProductVariation]{
+			Items:      resp.Variations,
+			TotalItems: resp.TotalItems,
+			TotalPages: resp.TotalPages,
+		}, nil
+	}
+}
+
+// Get retrieves a single variation of a product
+func (s *VariationService) Get(ctx context.Context, productID, id int) (*types.ProductVariation, error) {
+	opts := utils.RequestOptions{
+		Method:      http.MethodGet,
+		Path:        fmt.Sprintf("/wp-json/dokan/v1/products/%d/variations/%d", productID, id),
+		Operation:   "variations.get",
+		ResourceIDs: map[string]int{"product_id": productID, "variation_id": id},
+	}
+
+	resp, err := s.client.MakeRequest(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product variation: %w", err)
+	}
+
+	var variation types.ProductVariation
+	if err := utils.ParseJSON(resp.Body, &variation); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &variation, nil
+}
+
+// Create creates a new variation of a product
+func (s *VariationService) Create(ctx context.Context, productID int, variation *types.ProductVariation) (*types.ProductVariation, error) {
+	opts := utils.RequestOptions{
+		Method:      http.MethodPost,
+		Path:        fmt.Sprintf("/wp-json/dokan/v1/products/%d/variations", productID),
+		Operation:   "variations.create",
+		ResourceIDs: map[string]int{"product_id": productID},
+		Body:        variation,
+	}
+
+	resp, err := s.client.MakeRequest(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create product variation: %w", err)
+	}
+
+	var createdVariation types.ProductVariation
+	if err := utils.ParseJSON(resp.Body, &createdVariation); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &createdVariation, nil
+}
+
+// Update updates an existing variation of a product
+func (s *VariationService) Update(ctx context.Context, productID, id int, variation *types.ProductVariation) (*types.ProductVariation, error) {
+	opts := utils.RequestOptions{
+		Method:      http.MethodPut,
+		Path:        fmt.Sprintf("/wp-json/dokan/v1/products/%d/variations/%d", productID, id),
+		Operation:   "variations.update",
+		ResourceIDs: map[string]int{"product_id": productID, "variation_id": id},
+		Body:        variation,
+	}
+
+	resp, err := s.client.MakeRequest(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update product variation: %w", err)
+	}
+
+	var updatedVariation types.ProductVariation
+	if err := utils.ParseJSON(resp.Body, &updatedVariation); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &updatedVariation, nil
+}
+
+// Delete permanently deletes a variation of a product. Variations cannot be
+// moved to the trash, so the deletion is always forced.
+func (s *VariationService) Delete(ctx context.Context, productID, id int) error {
+	opts := utils.RequestOptions{
+		Method:      http.MethodDelete,
+		Path:        fmt.Sprintf("/wp-json/dokan/v1/products/%d/variations/%d", productID, id),
+		Operation:   "variations.delete",
+		ResourceIDs: map[string]int{"product_id": productID, "variation_id": id},
+		Query:       &deleteParams{Force: true},
+	}
+
+	_, err := s.client.MakeRequest(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to delete product variation: %w", err)
+	}
+
+	return nil
+}
+
+// Batch creates, updates and deletes variations of a product. Inputs larger
+// than the server limit are split into several requests; see batch.Run.
+func (s *VariationService) Batch(ctx context.Context, productID int, req *types.BatchRequest[types.ProductVariation], opts *batch.Options) (*batch.Result[types.ProductVariation], error) {
+	path := fmt.Sprintf("/wp-json/dokan/v1/products/%d/variations/batch", productID)
+	return batch.Run[types.ProductVariation, types.ProductVariation](ctx, batch.NewSender[types.ProductVariation](s.client, path, "variations.batch"), req, opts)
+}
+
+// VariationMatrix returns one variation for every combination of the
+// options of the product attributes marked with Variation == true, in the
+// order the attributes and options are declared. Only the Attributes of each
+// variation are set; prices, stock and other fields are left to the caller.
+func VariationMatrix(product *types.Product) []types.ProductVariation {
+	var attributes []types.ProductAttribute
+	for _, attribute := range product.Attributes {
+		if attribute.Variation && len(attribute.Options) > 0 {
+			attributes = append(attributes, attribute)
+		}
+	}
+
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	combinations := [][]types.VariationAttribute{{}}
+	for _, attribute := range attributes {
+		next := make([][]types.VariationAttribute, 0, len(combinations)*len(attribute.Options))
+		for _, combination := range combinations {
+			for _, option := range attribute.Options {
+				extended := make([]types.VariationAttribute, len(combination), len(combination)+1)
+				copy(extended, combination)
+				extended = append(extended, types.VariationAttribute{
+					ID:     attribute.ID,
+					Name:   attribute.Name,
+					Option: option,
+				})
+				next = append(next, extended)
+			}
+		}
+		combinations = next
+	}
+
+	variations := make([]types.ProductVariation, len(combinations))
+	for i, combination := range combinations {
+		variations[i].Attributes = combination
+	}
+
+	return variations
+}
+
+// VariationListResponse represents a paginated list of product variations
+type VariationListResponse struct {
+	Variations []types.ProductVariation `json:"variations"`
+	ProductID  int                      `json:"product_id"`
+	types.ListResponse
+}
+
+// deleteParams represents query parameters for delete requests
+type deleteParams struct {
+	Force bool `url:"force,omitempty"`
+}
@@ -0,0 +1,95 @@
+package products
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/types"
+	"github.com/diogenes-moreira/dokan-go-sdk/utils"
+)
+
+type fakeClient struct {
+	requests []utils.RequestOptions
+	response *utils.Response
+}
+
+func (c *fakeClient) MakeRequest(ctx context.Context, opts utils.RequestOptions) (*utils.Response, error) {
+	c.requests = append(c.requests, opts)
+	return c.response, nil
+}
+
+func TestVariationService_List(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("X-WP-Total", "3")
+	headers.Set("X-WP-TotalPages", "1")
+	client := &fakeClient{response: &utils.Response{
+		StatusCode: http.StatusOK,
+		Headers:    headers,
+		Body:       []byte(`[{"id": 1}, {"id": 2}, {"id": 3}]`),
+	}}
+
+	service := NewService(client)
+	resp, err := service.Variations.List(context.Background(), 10, nil)
+	if err != nil {
+		t.Fatalf("List() returned error: %v", err)
+	}
+
+	if len(resp.Variations) != 3 || resp.TotalItems != 3 || resp.ProductID != 10 {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	if client.requests[0].Path != "/wp-json/dokan/v1/products/10/variations" {
+		t.Errorf("Unexpected path: %s", client.requests[0].Path)
+	}
+}
+
+func TestVariationService_Batch(t *testing.T) {
+	client := &fakeClient{response: &utils.Response{
+		StatusCode: http.StatusOK,
+		Body:       []byte(`{"create": [{"id": 11}], "delete": [{"id": 5}]}`),
+	}}
+
+	service := NewVariationService(client)
+	result, err := service.Batch(context.Background(), 10, &types.BatchRequest[types.ProductVariation]{
+		Create: []types.ProductVariation{{SKU: "NEW"}},
+		Delete: []int{5},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Batch() returned error: %v", err)
+	}
+
+	if len(result.Create) != 1 || result.Create[0].Item.ID != 11 || len(result.Delete) != 1 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if client.requests[0].Method != http.MethodPost || client.requests[0].Path != "/wp-json/dokan/v1/products/10/variations/batch" {
+		t.Errorf("Unexpected request: %s %s", client.requests[0].Method, client.requests[0].Path)
+	}
+}
+
+func TestVariationMatrix(t *testing.T) {
+	product := &types.Product{
+		Attributes: []types.ProductAttribute{
+			{ID: 1, Name: "Color", Variation: true, Options: []string{"Red", "Blue"}},
+			{ID: 2, Name: "Material", Variation: false, Options: []string{"Cotton"}},
+			{ID: 3, Name: "Size", Variation: true, Options: []string{"S", "M", "L"}},
+		},
+	}
+
+	variations := VariationMatrix(product)
+	if len(variations) != 6 {
+		t.Fatalf("Expected 6 variations, got %d", len(variations))
+	}
+
+	first := variations[0].Attributes
+	if len(first) != 2 || first[0].Option != "Red" || first[1].Option != "S" {
+		t.Errorf("Unexpected first combination: %+v", first)
+	}
+	last := variations[5].Attributes
+	if last[0].Option != "Blue" || last[1].Option != "L" || last[1].ID != 3 {
+		t.Errorf("Unexpected last combination: %+v", last)
+	}
+
+	if VariationMatrix(&types.Product{}) != nil {
+		t.Error("Expected no variations for a product without variation attributes")
+	}
+}
@@ -0,0 +1,260 @@
+// Package ratelimit throttles requests on the client side with a token
+// bucket and a cap on concurrent requests. The rate adapts to the server: it
+// is cut whenever the server reports rate limiting and recovers gradually as
+// requests succeed.
+package ratelimit
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/errors"
+)
+
+// Default adaptation parameters used when the Config fields are zero
+const (
+	DefaultDecreaseFactor = 0.5
+	DefaultRecoverySteps  = 20
+)
+
+// Config configures a Limiter
+type Config struct {
+	// RequestsPerSecond is the target rate. Zero disables the rate limit,
+	// leaving only MaxConcurrent.
+	RequestsPerSecond float64
+
+	// Burst is the number of requests that may be sent at once after a
+	// quiet period. Defaults to 1.
+	Burst int
+
+	// MaxConcurrent caps the number of requests in flight. Zero means no
+	// limit.
+	MaxConcurrent int
+
+	// MinRequestsPerSecond is the lowest rate the limiter adapts down to.
+	// Defaults to a tenth of RequestsPerSecond.
+	MinRequestsPerSecond float64
+
+	// DecreaseFactor multiplies the current rate each time the server
+	// reports rate limiting. Defaults to DefaultDecreaseFactor.
+	DecreaseFactor float64
+
+	// RecoverySteps is the number of successful requests needed to recover
+	// from MinRequestsPerSecond to RequestsPerSecond. Defaults to
+	// DefaultRecoverySteps.
+	RecoverySteps int
+}
+
+// Stats reports how a Limiter has throttled requests
+type Stats struct {
+	// Requests is the number of requests admitted
+	Requests int64
+	// Delayed is the number of requests that had to wait
+	Delayed int64
+	// TotalWait is the time spent waiting by all requests
+	TotalWait time.Duration
+	// MaxWait is the longest time a single request waited
+	MaxWait time.Duration
+	// RateLimited is the number of rate limit responses observed
+	RateLimited int64
+	// CurrentRate is the rate in requests per second currently enforced
+	CurrentRate float64
+	// InFlight is the number of requests currently admitted and not released
+	InFlight int
+}
+
+// Limiter admits requests at an adaptive rate. It is safe for concurrent use
+// and is meant to be shared by every request sent to the same site.
+type Limiter struct {
+	config Config
+	slots  chan struct{}
+
+	mu          sync.Mutex
+	rate        float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	stats       Stats
+}
+
+// New creates a Limiter from config
+func New(config Config) *Limiter {
+	if config.Burst < 1 {
+		config.Burst = 1
+	}
+	if config.MinRequestsPerSecond <= 0 || config.MinRequestsPerSecond > config.RequestsPerSecond {
+		config.MinRequestsPerSecond = config.RequestsPerSecond / 10
+	}
+	if config.DecreaseFactor <= 0 || config.DecreaseFactor >= 1 {
+		config.DecreaseFactor = DefaultDecreaseFactor
+	}
+	if config.RecoverySteps < 1 {
+		config.RecoverySteps = DefaultRecoverySteps
+	}
+
+	l := &Limiter{
+		config: config,
+		rate:   config.RequestsPerSecond,
+		tokens: float64(config.Burst),
+		last:   time.Now(),
+	}
+	if config.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, config.MaxConcurrent)
+	}
+	return l
+}
+
+// Acquire waits until a request may be sent. On success the returned release
+// function must be called once the request completes, with its error, so
+// that the limiter can free the concurrency slot and adapt its rate.
+func (l *Limiter) Acquire(ctx context.Context) (release func(err error), err error) {
+	start := time.Now()
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if err := l.wait(ctx); err != nil {
+		if l.slots != nil {
+			<-l.slots
+		}
+		return nil, err
+	}
+
+	waited := time.Since(start)
+
+	l.mu.Lock()
+	l.stats.Requests++
+	l.stats.InFlight++
+	// Ignore the time spent on lock contention alone
+	if waited > time.Millisecond {
+		l.stats.Delayed++
+		l.stats.TotalWait += waited
+		l.stats.MaxWait = max(l.stats.MaxWait, waited)
+	}
+	l.mu.Unlock()
+
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() {
+			l.observe(err)
+			if l.slots != nil {
+				<-l.slots
+			}
+		})
+	}, nil
+}
+
+// wait reserves a token and sleeps until it becomes available
+func (l *Limiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	delay := l.pausedUntil.Sub(now)
+	reserved := false
+	if l.rate > 0 {
+		l.refill(now)
+		l.tokens--
+		reserved = true
+		if l.tokens < 0 {
+			delay = max(delay, time.Duration(-l.tokens/l.rate*float64(time.Second)))
+		}
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		if reserved {
+			// Give the reservation back to the requests still waiting
+			l.mu.Lock()
+			l.tokens++
+			l.mu.Unlock()
+		}
+		return ctx.Err()
+	}
+}
+
+// refill adds the tokens earned since the last call. l.mu must be held.
+func (l *Limiter) refill(now time.Time) {
+	elapsed := now.Sub(l.last).Seconds()
+	l.last = now
+	if elapsed > 0 {
+		l.tokens = min(l.tokens+elapsed*l.rate, float64(l.config.Burst))
+	}
+}
+
+// observe adapts the rate to the outcome of a request
+func (l *Limiter) observe(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stats.InFlight--
+	now := time.Now()
+
+	if !isRateLimited(err) {
+		if err == nil && l.config.RequestsPerSecond > 0 {
+			l.refill(now)
+			step := (l.config.RequestsPerSecond - l.config.MinRequestsPerSecond) / float64(l.config.RecoverySteps)
+			l.rate = min(l.rate+step, l.config.RequestsPerSecond)
+		}
+		return
+	}
+
+	l.stats.RateLimited++
+	if retryAfter, ok := errors.RetryAfter(err); ok {
+		l.pausedUntil = now.Add(retryAfter)
+	}
+	if l.config.RequestsPerSecond > 0 {
+		l.refill(now)
+		l.rate = max(l.rate*l.config.DecreaseFactor, l.config.MinRequestsPerSecond)
+		l.tokens = min(l.tokens, 0)
+	}
+}
+
+// Rate returns the rate in requests per second currently enforced
+func (l *Limiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.rate
+}
+
+// Stats returns a snapshot of the limiter statistics
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := l.stats
+	stats.CurrentRate = l.rate
+	return stats
+}
+
+// isRateLimited reports whether err means the server is overloaded: a
+// RateLimitError, or a 429 or 503 response
+func isRateLimited(err error) bool {
+	var rateLimitErr *errors.RateLimitError
+	if stderrors.As(err, &rateLimitErr) {
+		return true
+	}
+
+	var dokanErr *errors.DokanError
+	if stderrors.As(err, &dokanErr) {
+		return dokanErr.StatusCode == http.StatusTooManyRequests ||
+			dokanErr.StatusCode == http.StatusServiceUnavailable
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/errors"
+)
+
+func TestLimiter_Rate(t *testing.T) {
+	limiter := New(Config{RequestsPerSecond: 50, Burst: 1})
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		release, err := limiter.Acquire(context.Background())
+		if err != nil {
+			t.Fatalf("Acquire() returned error: %v", err)
+		}
+		release(nil)
+	}
+
+	// The first request uses the burst, the other 5 wait 20ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected requests to be spaced out, took %v", elapsed)
+	}
+
+	stats := limiter.Stats()
+	if stats.Requests != 6 || stats.Delayed == 0 || stats.TotalWait == 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestLimiter_MaxConcurrent(t *testing.T) {
+	limiter := New(Config{MaxConcurrent: 2})
+
+	var inFlight, peak atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := limiter.Acquire(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			n := inFlight.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			inFlight.Add(-1)
+			release(nil)
+		}()
+	}
+	wg.Wait()
+
+	if peak.Load() > 2 {
+		t.Errorf("Expected at most 2 requests in flight, got %d", peak.Load())
+	}
+	if stats := limiter.Stats(); stats.InFlight != 0 || stats.Requests != 8 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestLimiter_Adapts(t *testing.T) {
+	limiter := New(Config{RequestsPerSecond: 100, Burst: 100, MinRequestsPerSecond: 10, RecoverySteps: 3})
+
+	release, _ := limiter.Acquire(context.Background())
+	release(errors.NewRateLimitError(0))
+	if rate := limiter.Rate(); rate != 50 {
+		t.Errorf("Expected rate to halve to 50, got %v", rate)
+	}
+
+	release, _ = limiter.Acquire(context.Background())
+	release(errors.NewDokanError("http_error", "HTTP 503 error", 503))
+	if rate := limiter.Rate(); rate != 25 {
+		t.Errorf("Expected rate to halve to 25, got %v", rate)
+	}
+
+	release, _ = limiter.Acquire(context.Background())
+	release(errors.NewNotFoundError("product", 1))
+	if rate := limiter.Rate(); rate != 25 {
+		t.Errorf("Expected other errors to keep the rate, got %v", rate)
+	}
+
+	for i := 0; i < 3; i++ {
+		release, _ = limiter.Acquire(context.Background())
+		release(nil)
+	}
+	if rate := limiter.Rate(); rate != 100 {
+		t.Errorf("Expected rate to recover to 100, got %v", rate)
+	}
+	if stats := limiter.Stats(); stats.RateLimited != 2 {
+		t.Errorf("Expected 2 rate limited responses, got %d", stats.RateLimited)
+	}
+}
+
+func TestLimiter_RetryAfterPauses(t *testing.T) {
+	limiter := New(Config{MaxConcurrent: 4})
+
+	release, _ := limiter.Acquire(context.Background())
+	release(errors.NewRateLimitError(30))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.Acquire(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected requests to wait for Retry-After, got %v", err)
+	}
+	if stats := limiter.Stats(); stats.InFlight != 0 {
+		t.Errorf("Expected no requests in flight, got %d", stats.InFlight)
+	}
+}
@@ -2,9 +2,12 @@ package stores
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 
+	"github.com/diogenes-moreira/dokan-go-sdk/pagination"
 	"github.com/diogenes-moreira/dokan-go-sdk/types"
 	"github.com/diogenes-moreira/dokan-go-sdk/utils"
 )
@@ -27,8 +30,10 @@ func NewService(client ClientInterface) *Service {
 // Get retrieves a single store by vendor ID
 func (s *Service) Get(ctx context.Context, vendorID int) (*types.Store, error) {
 	opts := utils.RequestOptions{
-		Method: http.MethodGet,
-		Path:   fmt.Sprintf("/wp-json/dokan/v1/stores/%d", vendorID),
+		Method:      http.MethodGet,
+		Path:        fmt.Sprintf("/wp-json/dokan/v1/stores/%d", vendorID),
+		Operation:   "stores.get",
+		ResourceIDs: map[string]int{"vendor_id": vendorID},
 	}
 	
 	resp, err := s.client.MakeRequest(ctx, opts)
@@ -47,9 +52,10 @@ func (s *Service) Get(ctx context.Context, vendorID int) (*types.Store, error) {
 // List retrieves a list of stores with optional filtering
 func (s *Service) List(ctx context.Context, params *types.StoreListParams) (*StoreListResponse, error) {
 	opts := utils.RequestOptions{
-		Method: http.MethodGet,
-		Path:   "/wp-json/dokan/v1/stores/",
-		Query:  params,
+		Method:    http.MethodGet,
+		Path:      "/wp-json/dokan/v1/stores/",
+		Operation: "stores.list",
+		Query:     params,
 	}
 	
 	resp, err := s.client.MakeRequest(ctx, opts)
@@ -82,9 +88,11 @@ func (s *Service) List(ctx context.Context, params *types.StoreListParams) (*Sto
 // GetProducts retrieves products for a specific store
 func (s *Service) GetProducts(ctx context.Context, vendorID int, params *types.ProductListParams) (*StoreProductsResponse, error) {
 	opts := utils.RequestOptions{
-		Method: http.MethodGet,
-		Path:   fmt.Sprintf("/wp-json/dokan/v1/stores/%d/products", vendorID),
-		Query:  params,
+		Method:      http.MethodGet,
+		Path:        fmt.Sprintf("/wp-json/dokan/v1/stores/%d/products", vendorID),
+		Operation:   "stores.products",
+		ResourceIDs: map[string]int{"vendor_id": vendorID},
+		Query:       params,
 	}
 	
 	resp, err := s.client.MakeRequest(ctx, opts)
@@ -118,9 +126,11 @@ func (s *Service) GetProducts(ctx context.Context, vendorID int, params *types.P
 // GetReviews retrieves reviews for a specific store
 func (s *Service) GetReviews(ctx context.Context, vendorID int, params *ReviewListParams) (*StoreReviewsResponse, error) {
 	opts := utils.RequestOptions{
-		Method: http.MethodGet,
-		Path:   fmt.Sprintf("/wp-json/dokan/v1/stores/%d/reviews", vendorID),
-		Query:  params,
+		Method:      http.MethodGet,
+		Path:        fmt.Sprintf("/wp-json/dokan/v1/stores/%d/reviews", vendorID),
+		Operation:   "stores.reviews",
+		ResourceIDs: map[string]int{"vendor_id": vendorID},
+		Query:       params,
 	}
 	
 	resp, err := s.client.MakeRequest(ctx, opts)
@@ -151,6 +161,139 @@ func (s *Service) GetReviews(ctx context.Context, vendorID int, params *ReviewLi
 	return listResponse, nil
 }
 
+// Iterate returns a cursor over every store matching params, fetching
+// pages on demand. params.Page is ignored; use opts.StartPage instead.
+func (s *Service) Iterate(ctx context.Context, params *types.StoreListParams, opts *pagination.Options) *pagination.Iterator[types.Store] {
+	return pagination.NewIterator(ctx, s.storePageFetcher(params), opts)
+}
+
+// All returns an iterator over every store matching params across all pages
+func (s *Service) All(ctx context.Context, params *types.StoreListParams, opts *pagination.Options) iter.Seq2[types.Store, error] {
+	return pagination.All(ctx, s.storePageFetcher(params), opts)
+}
+
+// IterateProducts returns a cursor over every product of a store, fetching
+// pages on demand. params.Page is ignored; use opts.StartPage instead.
+func (s *Service) IterateProducts(ctx context.Context, vendorID int, params *types.ProductListParams, opts *pagination.Options) *pagination.Iterator[types.Product] {
+	return pagination.NewIterator(ctx, s.productPageFetcher(vendorID, params), opts)
+}
+
+// AllProducts returns an iterator over every product of a store across all pages
+func (s *Service) AllProducts(ctx context.Context, vendorID int, params *types.ProductListParams, opts *pagination.Options) iter.Seq2[types.Product, error] {
+	return pagination.All(ctx, s.productPageFetcher(vendorID, params), opts)
+}
+
+// IterateReviews returns a cursor over every review of a store, fetching
+// pages on demand. params.Page is ignored; use opts.StartPage instead.
+func (s *Service) IterateReviews(ctx context.Context, vendorID int, params *ReviewListParams, opts *pagination.Options) *pagination.Iterator[Review] {
+	return pagination.NewIterator(ctx, s.reviewPageFetcher(vendorID, params), opts)
+}
+
+// AllReviews returns an iterator over every review of a store across all pages
+func (s *Service) AllReviews(ctx context.Context, vendorID int, params *ReviewListParams, opts *pagination.Options) iter.Seq2[Review, error] {
+	return pagination.All(ctx, s.reviewPageFetcher(vendorID, params), opts)
+}
+
+// ListAllConcurrent fetches every store matching params. The first page is
+// fetched to learn the page count, then the remaining pages are fetched in
+// parallel with up to opts.Concurrency workers. Results keep page order.
+func (s *Service) ListAllConcurrent(ctx context.Context, params *types.StoreListParams, opts *pagination.Options) ([]types.Store, error) {
+	stores, err := pagination.FetchAll(ctx, s.storePageFetcher(params), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list all stores: %w", err)
+	}
+
+	return stores, nil
+}
+
+// GetAllProductsConcurrent fetches every product of a store, fetching the
+// pages after the first in parallel. Results keep page order.
+func (s *Service) GetAllProductsConcurrent(ctx context.Context, vendorID int, params *types.ProductListParams, opts *pagination.Options) ([]types.Product, error) {
+	products, err := pagination.FetchAll(ctx, s.productPageFetcher(vendorID, params), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list all store products: %w", err)
+	}
+
+	return products, nil
+}
+
+// GetAllReviewsConcurrent fetches every review of a store, fetching the
+// pages after the first in parallel. Results keep page order.
+func (s *Service) GetAllReviewsConcurrent(ctx context.Context, vendorID int, params *ReviewListParams, opts *pagination.Options) ([]Review, error) {
+	reviews, err := pagination.FetchAll(ctx, s.reviewPageFetcher(vendorID, params), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list all store reviews: %w", err)
+	}
+
+	return reviews, nil
+}
+
+// storePageFetcher returns a fetcher that lists a single page of stores
+func (s *Service) storePageFetcher(params *types.StoreListParams) pagination.Fetcher[types.Store] {
+	return func(ctx context.Context, page int) (*pagination.Page[types.Store], error) {
+		var pageParams types.StoreListParams
+		if params != nil {
+			pageParams = *params
+		}
+		pageParams.Page = page
+
+		resp, err := s.List(ctx, &pageParams)
+		if err != nil {
+			return nil, err
+		}
+
+		return &pagination.Page[types.Store]{
+			Items:      resp.Stores,
+			TotalItems: resp.TotalItems,
+			TotalPages: resp.TotalPages,
+		}, nil
+	}
+}
+
+// productPageFetcher returns a fetcher that lists a single page of store products
+func (s *Service) productPageFetcher(vendorID int, params *types.ProductListParams) pagination.Fetcher[types.Product] {
+	return func(ctx context.Context, page int) (*pagination.Page[types.Product], error) {
+		var pageParams types.ProductListParams
+		if params != nil {
+			pageParams = *params
+		}
+		pageParams.Page = page
+
+		resp, err := s.GetProducts(ctx, vendorID, &pageParams)
+		if err != nil {
+			return nil, err
+		}
+
+		return &pagination.Page[types.Product]{
+			Items:      resp.Products,
+			TotalItems: resp.TotalItems,
+			TotalPages: resp.TotalPages,
+		}, nil
+	}
+}
+
+// reviewPageFetcher returns a fetcher that lists a single page of store reviews
+func (s *Service) reviewPageFetcher(vendorID int, params *ReviewListParams) pagination.Fetcher[Review] {
+	return func(ctx context.Context, page int) (*pagination.Page[Review], error) {
+		var pageParams ReviewListParams
+		if params != nil {
+			pageParams = *params
+		}
+		pageParams.Page = page
+
+		resp, err := s.GetReviews(ctx, vendorID, &pageParams)
+		if err != nil {
+			return nil, err
+		}
+
+		return &pagination.Page[Review]{
+			Items:      resp.Reviews,
+			TotalItems: resp.TotalItems,
+			TotalPages: resp.TotalPages,
+		}, nil
+	}
+}
+
 // StoreListResponse represents a paginated list of stores
 type StoreListResponse struct {
 	Stores []types.Store `json:"stores"`
@@ -173,16 +316,28 @@ type StoreReviewsResponse struct {
 
 // Review represents a store review
 type Review struct {
-	ID           int    `json:"id"`
-	ProductID    int    `json:"product_id"`
+	ID           types.FlexInt `json:"id"`
+	ProductID    types.FlexInt `json:"product_id"`
 	Status       string `json:"status"`
 	Reviewer     string `json:"reviewer"`
 	ReviewerEmail string `json:"reviewer_email"`
 	Review       string `json:"review"`
-	Rating       int    `json:"rating"`
-	Verified     bool   `json:"verified"`
-	DateCreated  string `json:"date_created"`
-	DateCreatedGMT string `json:"date_created_gmt"`
+	Rating       types.FlexInt `json:"rating"`
+	Verified     types.FlexBool `json:"verified"`
+	DateCreated  *types.WPTime `json:"date_created"`
+	DateCreatedGMT *types.WPTime `json:"date_created_gmt"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, pairing the local date with
+// its _gmt sibling
+func (r *Review) UnmarshalJSON(data []byte) error {
+	type review Review
+	if err := json.Unmarshal(data, (*review)(r)); err != nil {
+		return err
+	}
+
+	types.PairWPTime(r.DateCreated, r.DateCreatedGMT)
+	return nil
 }
 
 // ReviewListParams represents parameters for listing reviews
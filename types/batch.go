@@ -0,0 +1,18 @@
+package types
+
+// BatchRequest represents a batch of create, update and delete operations
+// sent to a WooCommerce-style /batch endpoint
+type BatchRequest[T any] struct {
+	Create []T   `json:"create,omitempty"`
+	Update []T   `json:"update,omitempty"`
+	Delete []int `json:"delete,omitempty"`
+}
+
+// BatchResponse represents the response of a /batch endpoint. Each item is
+// either the saved entity or an object with an "error" field, so services
+// decode it as BatchResponse[json.RawMessage].
+type BatchResponse[T any] struct {
+	Create []T `json:"create,omitempty"`
+	Update []T `json:"update,omitempty"`
+	Delete []T `json:"delete,omitempty"`
+}
@@ -0,0 +1,358 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode specifies how a Decimal is rounded to fewer digits
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest value, ties away from zero. This is
+	// the behaviour of PHP's round(), used by WooCommerce.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest value, ties to the even digit
+	RoundHalfEven
+	// RoundHalfDown rounds to the nearest value, ties toward zero
+	RoundHalfDown
+	// RoundUp rounds away from zero
+	RoundUp
+	// RoundDown rounds toward zero, truncating the extra digits
+	RoundDown
+	// RoundCeiling rounds toward positive infinity
+	RoundCeiling
+	// RoundFloor rounds toward negative infinity
+	RoundFloor
+)
+
+// maxDecimalExponent bounds the exponent of parsed decimals, so that input
+// such as "1e999999999" from the API cannot allocate a huge number
+const maxDecimalExponent = 1000
+
+// Decimal is an arbitrary-precision decimal number used for prices and
+// totals. The API encodes amounts as strings such as "19.90"; Decimal keeps
+// every digit, so sums do not drift the way float64 does.
+//
+// The zero value holds no amount and is encoded as an empty string. It
+// behaves as 0 in arithmetic. Use Sign to test for a numeric zero.
+type Decimal struct {
+	value *big.Int
+	scale int32
+}
+
+// NewDecimal creates a Decimal equal to value * 10^-scale, e.g.
+// NewDecimal(1999, 2) is 19.99
+func NewDecimal(value int64, scale int32) Decimal {
+	d := Decimal{value: big.NewInt(value), scale: scale}
+	if scale < 0 {
+		d.value.Mul(d.value, pow10(-scale))
+		d.scale = 0
+	}
+	return d
+}
+
+// NewDecimalFromInt creates a Decimal from an integer
+func NewDecimalFromInt(value int64) Decimal {
+	return NewDecimal(value, 0)
+}
+
+// NewDecimalFromFloat creates a Decimal from the shortest decimal
+// representation of a float64
+func NewDecimalFromFloat(value float64) Decimal {
+	d, err := ParseDecimal(strconv.FormatFloat(value, 'f', -1, 64))
+	if err != nil {
+		return Decimal{}
+	}
+	return d
+}
+
+// ParseDecimal parses a decimal string such as "19.99", "-0.5" or "1e3".
+// An empty string yields the zero Decimal. Exponents beyond ±1000 are
+// rejected.
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Decimal{}, nil
+	}
+
+	mantissa := s
+	var exponent int64
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal: %q", s)
+		}
+		if exp > maxDecimalExponent || exp < -maxDecimalExponent {
+			return Decimal{}, fmt.Errorf("decimal exponent out of range: %q", s)
+		}
+		mantissa = s[:i]
+		exponent = exp
+	}
+
+	negative := false
+	switch {
+	case strings.HasPrefix(mantissa, "-"):
+		negative = true
+		mantissa = mantissa[1:]
+	case strings.HasPrefix(mantissa, "+"):
+		mantissa = mantissa[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(mantissa, ".")
+	digits := intPart + fracPart
+	if digits == "" {
+		return Decimal{}, fmt.Errorf("invalid decimal: %q", s)
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return Decimal{}, fmt.Errorf("invalid decimal: %q", s)
+		}
+	}
+
+	value, _ := new(big.Int).SetString(digits, 10)
+	if negative {
+		value.Neg(value)
+	}
+
+	scale := int64(len(fracPart)) - exponent
+	if scale < 0 {
+		value.Mul(value, pow10(int32(-scale)))
+		scale = 0
+	}
+
+	return Decimal{value: value, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s is not a valid decimal
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// SumDecimals returns the exact sum of values
+func SumDecimals(values ...Decimal) Decimal {
+	sum := NewDecimalFromInt(0)
+	for _, v := range values {
+		sum = sum.Add(v)
+	}
+	return sum
+}
+
+// IsZero reports whether d is the zero Decimal, i.e. it holds no amount.
+// A parsed "0" is not zero in this sense; use Sign to test for 0.
+func (d Decimal) IsZero() bool {
+	return d.value == nil
+}
+
+// Scale returns the number of digits after the decimal point
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d
+func (d Decimal) Sign() int {
+	if d.value == nil {
+		return 0
+	}
+	return d.value.Sign()
+}
+
+// Add returns d + other
+func (d Decimal) Add(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+	value := new(big.Int).Add(d.rescaled(scale), other.rescaled(scale))
+	return Decimal{value: value, scale: scale}
+}
+
+// Sub returns d - other
+func (d Decimal) Sub(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+	value := new(big.Int).Sub(d.rescaled(scale), other.rescaled(scale))
+	return Decimal{value: value, scale: scale}
+}
+
+// Mul returns d * other
+func (d Decimal) Mul(other Decimal) Decimal {
+	value := new(big.Int).Mul(d.bigValue(), other.bigValue())
+	return Decimal{value: value, scale: d.scale + other.scale}
+}
+
+// Quo returns d / other rounded to places digits after the decimal point.
+// It panics if other is 0.
+func (d Decimal) Quo(other Decimal, places int32, mode RoundingMode) Decimal {
+	numerator := new(big.Int).Set(d.bigValue())
+	denominator := new(big.Int).Set(other.bigValue())
+
+	shift := places - d.scale + other.scale
+	if shift >= 0 {
+		numerator.Mul(numerator, pow10(shift))
+	} else {
+		denominator.Mul(denominator, pow10(-shift))
+	}
+
+	return Decimal{value: divRound(numerator, denominator, mode), scale: places}
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{value: new(big.Int).Neg(d.bigValue()), scale: d.scale}
+}
+
+// Abs returns |d|
+func (d Decimal) Abs() Decimal {
+	return Decimal{value: new(big.Int).Abs(d.bigValue()), scale: d.scale}
+}
+
+// Round returns d rounded to exactly places digits after the decimal point.
+// A negative places rounds to a multiple of 10^-places with no digits after
+// the point, e.g. -2 rounds 1250 to 1300 with RoundHalfUp.
+func (d Decimal) Round(places int32, mode RoundingMode) Decimal {
+	if places < 0 {
+		value := divRound(d.bigValue(), pow10(d.scale-places), mode)
+		return Decimal{value: value.Mul(value, pow10(-places)), scale: 0}
+	}
+	if places >= d.scale {
+		return Decimal{value: d.rescaled(places), scale: places}
+	}
+
+	value := divRound(d.bigValue(), pow10(d.scale-places), mode)
+	return Decimal{value: value, scale: places}
+}
+
+// Cmp compares d and other and returns -1, 0 or +1
+func (d Decimal) Cmp(other Decimal) int {
+	scale := max(d.scale, other.scale)
+	return d.rescaled(scale).Cmp(other.rescaled(scale))
+}
+
+// Equal reports whether d and other represent the same number
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Float64 returns the nearest float64 to d
+func (d Decimal) Float64() float64 {
+	if d.value == nil {
+		return 0
+	}
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns d in plain decimal notation keeping its scale, e.g. "19.90".
+// The zero Decimal returns an empty string.
+func (d Decimal) String() string {
+	if d.value == nil {
+		return ""
+	}
+
+	digits := new(big.Int).Abs(d.value).String()
+	if d.scale > 0 {
+		if len(digits) <= int(d.scale) {
+			digits = strings.Repeat("0", int(d.scale)-len(digits)+1) + digits
+		}
+		point := len(digits) - int(d.scale)
+		digits = digits[:point] + "." + digits[point:]
+	}
+
+	if d.value.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// MarshalJSON implements json.Marshaler. Amounts are encoded as strings,
+// as the API expects.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting both numbers and
+// strings. Empty strings and null decode to the zero Decimal.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*d = Decimal{}
+		return nil
+	}
+
+	value := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := ParseDecimal(value)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
+
+// bigValue returns the unscaled value of d, treating the zero Decimal as 0
+func (d Decimal) bigValue() *big.Int {
+	if d.value == nil {
+		return new(big.Int)
+	}
+	return d.value
+}
+
+// rescaled returns the unscaled value of d at a scale no smaller than its own
+func (d Decimal) rescaled(scale int32) *big.Int {
+	value := new(big.Int).Set(d.bigValue())
+	if scale > d.scale {
+		value.Mul(value, pow10(scale-d.scale))
+	}
+	return value
+}
+
+// pow10 returns 10^n
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// divRound divides numerator by denominator, rounding with mode
+func divRound(numerator, denominator *big.Int, mode RoundingMode) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient
+	}
+
+	sign := numerator.Sign() * denominator.Sign()
+	half := new(big.Int).Abs(remainder)
+	half.Lsh(half, 1)
+	cmpHalf := half.Cmp(new(big.Int).Abs(denominator))
+
+	var increment bool
+	switch mode {
+	case RoundHalfUp:
+		increment = cmpHalf >= 0
+	case RoundHalfDown:
+		increment = cmpHalf > 0
+	case RoundHalfEven:
+		increment = cmpHalf > 0 || (cmpHalf == 0 && quotient.Bit(0) == 1)
+	case RoundUp:
+		increment = true
+	case RoundDown:
+		increment = false
+	case RoundCeiling:
+		increment = sign > 0
+	case RoundFloor:
+		increment = sign < 0
+	}
+
+	if increment {
+		quotient.Add(quotient, big.NewInt(int64(sign)))
+	}
+	return quotient
+}
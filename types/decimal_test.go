@@ -0,0 +1,191 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"19.99", "19.99"},
+		{"19.90", "19.90"},
+		{"-0.5", "-0.5"},
+		{".5", "0.5"},
+		{"+3", "3"},
+		{"1e3", "1000"},
+		{"1.25e-1", "0.125"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.input)
+		if err != nil {
+			t.Errorf("ParseDecimal(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if d.String() != tt.expected {
+			t.Errorf("ParseDecimal(%q) = %q, expected %q", tt.input, d.String(), tt.expected)
+		}
+	}
+
+	for _, invalid := range []string{"abc", "1.2.3", "-", "1e", "1e999999999", "1e-999999999"} {
+		if _, err := ParseDecimal(invalid); err == nil {
+			t.Errorf("ParseDecimal(%q) should return error", invalid)
+		}
+	}
+}
+
+func TestDecimal_ExactSum(t *testing.T) {
+	sum := SumDecimals()
+	for i := 0; i < 10; i++ {
+		sum = sum.Add(MustParseDecimal("0.1"))
+	}
+
+	if !sum.Equal(NewDecimalFromInt(1)) {
+		t.Errorf("Expected 10 x 0.1 to equal 1, got %s", sum)
+	}
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a := MustParseDecimal("19.99")
+	b := MustParseDecimal("5.5")
+
+	if got := a.Add(b).String(); got != "25.49" {
+		t.Errorf("Expected 25.49, got %s", got)
+	}
+	if got := a.Sub(b).String(); got != "14.49" {
+		t.Errorf("Expected 14.49, got %s", got)
+	}
+	if got := a.Mul(NewDecimalFromInt(3)).String(); got != "59.97" {
+		t.Errorf("Expected 59.97, got %s", got)
+	}
+	if got := NewDecimalFromInt(10).Quo(NewDecimalFromInt(3), 2, RoundHalfUp).String(); got != "3.33" {
+		t.Errorf("Expected 3.33, got %s", got)
+	}
+	if got := (Decimal{}).Add(a).String(); got != "19.99" {
+		t.Errorf("Expected the zero Decimal to behave as 0, got %s", got)
+	}
+}
+
+func TestDecimal_Round(t *testing.T) {
+	tests := []struct {
+		input    string
+		mode     RoundingMode
+		expected string
+	}{
+		{"2.345", RoundHalfUp, "2.35"},
+		{"-2.345", RoundHalfUp, "-2.35"},
+		{"2.345", RoundHalfDown, "2.34"},
+		{"2.345", RoundHalfEven, "2.34"},
+		{"2.355", RoundHalfEven, "2.36"},
+		{"2.341", RoundUp, "2.35"},
+		{"2.349", RoundDown, "2.34"},
+		{"-2.341", RoundCeiling, "-2.34"},
+		{"-2.341", RoundFloor, "-2.35"},
+		{"2.5", RoundHalfUp, "2.50"},
+	}
+
+	for _, tt := range tests {
+		got := MustParseDecimal(tt.input).Round(2, tt.mode).String()
+		if got != tt.expected {
+			t.Errorf("Round(%s, %d) = %s, expected %s", tt.input, tt.mode, got, tt.expected)
+		}
+	}
+}
+
+func TestDecimal_Round_NegativePlaces(t *testing.T) {
+	tests := []struct {
+		input    string
+		places   int32
+		mode     RoundingMode
+		expected string
+	}{
+		{"1250", -2, RoundHalfUp, "1300"},
+		{"1250", -2, RoundHalfEven, "1200"},
+		{"1234.56", -1, RoundDown, "1230"},
+		{"-1234.56", -1, RoundFloor, "-1240"},
+		{"1234.56", -3, RoundHalfUp, "1000"},
+		{"49.99", -2, RoundHalfUp, "0"},
+		{"50", -2, RoundCeiling, "100"},
+	}
+
+	for _, tt := range tests {
+		got := MustParseDecimal(tt.input).Round(tt.places, tt.mode)
+		if got.String() != tt.expected {
+			t.Errorf("Round(%s, %d) = %s, expected %s", tt.input, tt.places, got, tt.expected)
+		}
+		if got.Scale() != 0 {
+			t.Errorf("Round(%s, %d) has scale %d, expected 0", tt.input, tt.places, got.Scale())
+		}
+	}
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	var item LineItem
+	data := []byte(`{"subtotal": "10.00", "total": 12.5, "price": 6.25, "total_tax": ""}`)
+	if err := json.Unmarshal(data, &item); err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+
+	if item.Subtotal.String() != "10.00" {
+		t.Errorf("Expected subtotal 10.00, got %s", item.Subtotal)
+	}
+	if item.Total.String() != "12.5" {
+		t.Errorf("Expected total 12.5, got %s", item.Total)
+	}
+	if item.Price.String() != "6.25" {
+		t.Errorf("Expected price 6.25, got %s", item.Price)
+	}
+	if !item.TotalTax.IsZero() {
+		t.Errorf("Expected empty total_tax, got %s", item.TotalTax)
+	}
+
+	out, err := json.Marshal(Product{Name: "Shirt", RegularPrice: MustParseDecimal("19.90")})
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(out, &fields); err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+	if fields["regular_price"] != "19.90" {
+		t.Errorf("Expected regular_price \"19.90\", got %v", fields["regular_price"])
+	}
+	if _, ok := fields["sale_price"]; ok {
+		t.Error("Expected empty sale_price to be omitted")
+	}
+}
+
+func TestMoney(t *testing.T) {
+	order := Order{
+		Currency: "usd",
+		LineItems: []LineItem{
+			{Total: MustParseDecimal("0.10")},
+			{Total: MustParseDecimal("0.20")},
+		},
+	}
+
+	total := order.LineItemsTotal()
+	if total.String() != "0.30 USD" {
+		t.Errorf("Expected 0.30 USD, got %s", total)
+	}
+
+	if _, err := total.Add(NewMoney(NewDecimalFromInt(1), "EUR")); err == nil {
+		t.Error("Add() should fail for different currencies")
+	}
+
+	settings := DecimalSettings{Decimals: 2, Rounding: RoundHalfUp, DecimalSeparator: ",", ThousandSeparator: "."}
+	formatted := NewMoney(MustParseDecimal("-1234567.895"), "EUR").Format(settings)
+	if formatted != "-1.234.567,90" {
+		t.Errorf("Expected -1.234.567,90, got %s", formatted)
+	}
+
+	yen := NewMoney(MustParseDecimal("1999.5"), "JPY").RoundToCurrency(RoundHalfUp)
+	if yen.Amount.String() != "2000" {
+		t.Errorf("Expected 2000, got %s", yen.Amount)
+	}
+}
@@ -0,0 +1,120 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FlexInt is an int that also decodes from quoted numbers ("12"), integral
+// floats (12.0), empty strings and null. Dokan and third-party plugins are
+// not consistent about the JSON type of numeric fields.
+type FlexInt int
+
+// UnmarshalJSON implements json.Unmarshaler
+func (i *FlexInt) UnmarshalJSON(data []byte) error {
+	value, ok, err := flexScalar(data)
+	if err != nil || !ok {
+		*i = 0
+		return err
+	}
+
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		*i = FlexInt(n)
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f != math.Trunc(f) {
+		return fmt.Errorf("invalid integer value: %s", data)
+	}
+
+	*i = FlexInt(f)
+	return nil
+}
+
+// Int returns i as an int
+func (i FlexInt) Int() int {
+	return int(i)
+}
+
+// FlexFloat is a float64 that also decodes from quoted numbers ("4.5"),
+// empty strings and null.
+type FlexFloat float64
+
+// UnmarshalJSON implements json.Unmarshaler
+func (f *FlexFloat) UnmarshalJSON(data []byte) error {
+	value, ok, err := flexScalar(data)
+	if err != nil || !ok {
+		*f = 0
+		return err
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number value: %s", data)
+	}
+
+	*f = FlexFloat(n)
+	return nil
+}
+
+// Float64 returns f as a float64
+func (f FlexFloat) Float64() float64 {
+	return float64(f)
+}
+
+// FlexBool is a bool that also decodes from the values WordPress uses for
+// options: "yes"/"no", "on"/"off", "1"/"0", 1/0, empty strings and null.
+type FlexBool bool
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *FlexBool) UnmarshalJSON(data []byte) error {
+	value, ok, err := flexScalar(data)
+	if err != nil || !ok {
+		*b = false
+		return err
+	}
+
+	switch strings.ToLower(value) {
+	case "1", "true", "yes", "on", "y":
+		*b = true
+	case "0", "false", "no", "off", "n":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean value: %s", data)
+	}
+
+	return nil
+}
+
+// Bool returns b as a bool
+func (b FlexBool) Bool() bool {
+	return bool(b)
+}
+
+// flexScalar returns the textual form of a JSON scalar, unquoting strings.
+// ok is false for null and empty strings.
+func flexScalar(data []byte) (value string, ok bool, err error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return "", false, nil
+	}
+
+	if data[0] == '"' {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return "", false, err
+		}
+		value = strings.TrimSpace(value)
+		return value, value != "", nil
+	}
+
+	if data[0] == '{' || data[0] == '[' {
+		return "", false, fmt.Errorf("unexpected JSON value: %s", data)
+	}
+
+	return string(data), true, nil
+}
@@ -0,0 +1,159 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFlexInt_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected FlexInt
+	}{
+		{`12`, 12},
+		{`"12"`, 12},
+		{`12.0`, 12},
+		{`""`, 0},
+		{`null`, 0},
+	}
+
+	for _, tt := range tests {
+		var i FlexInt
+		if err := json.Unmarshal([]byte(tt.input), &i); err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %v", tt.input, err)
+			continue
+		}
+		if i != tt.expected {
+			t.Errorf("Unmarshal(%s) = %d, expected %d", tt.input, i, tt.expected)
+		}
+	}
+
+	var i FlexInt
+	if err := json.Unmarshal([]byte(`"12.5"`), &i); err == nil {
+		t.Error("Unmarshal() should return error for a fractional value")
+	}
+}
+
+func TestFlexFloat_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected FlexFloat
+	}{
+		{`4.5`, 4.5},
+		{`"4.5"`, 4.5},
+		{`""`, 0},
+		{`null`, 0},
+	}
+
+	for _, tt := range tests {
+		var f FlexFloat
+		if err := json.Unmarshal([]byte(tt.input), &f); err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %v", tt.input, err)
+			continue
+		}
+		if f != tt.expected {
+			t.Errorf("Unmarshal(%s) = %v, expected %v", tt.input, f, tt.expected)
+		}
+	}
+}
+
+func TestFlexBool_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected FlexBool
+	}{
+		{`true`, true},
+		{`false`, false},
+		{`"yes"`, true},
+		{`"no"`, false},
+		{`"on"`, true},
+		{`""`, false},
+		{`1`, true},
+		{`"0"`, false},
+		{`null`, false},
+	}
+
+	for _, tt := range tests {
+		var b FlexBool
+		if err := json.Unmarshal([]byte(tt.input), &b); err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %v", tt.input, err)
+			continue
+		}
+		if b != tt.expected {
+			t.Errorf("Unmarshal(%s) = %v, expected %v", tt.input, b, tt.expected)
+		}
+	}
+
+	var b FlexBool
+	if err := json.Unmarshal([]byte(`"maybe"`), &b); err == nil {
+		t.Error("Unmarshal() should return error for an unknown value")
+	}
+}
+
+func TestStore_UnmarshalJSON_LenientFields(t *testing.T) {
+	data := []byte(`[{
+		"id": 7,
+		"store_name": "Acme",
+		"show_email": "yes",
+		"enabled": "on",
+		"featured": "",
+		"rating": {"rating": "4.50", "count": "12"}
+	}]`)
+
+	var stores []Store
+	if err := json.Unmarshal(data, &stores); err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+
+	store := stores[0]
+	if !store.ShowEmail || !store.Enabled || store.Featured {
+		t.Errorf("Unexpected boolean fields: %+v", store)
+	}
+	if store.Rating == nil || store.Rating.Rating != 4.5 || store.Rating.Count != 12 {
+		t.Errorf("Unexpected rating: %+v", store.Rating)
+	}
+}
+
+func TestProductAndOrder_UnmarshalJSON_QuotedIDs(t *testing.T) {
+	var products []Product
+	err := json.Unmarshal([]byte(`[{
+		"id": "123",
+		"name": "Shirt",
+		"shipping_class_id": "4",
+		"categories": [{"id": "9", "name": "Clothing", "slug": "clothing"}],
+		"tags": [{"id": 10, "name": "Sale", "slug": "sale"}],
+		"images": [{"id": "11", "src": "https://example.com/shirt.jpg"}]
+	}]`), &products)
+	if err != nil {
+		t.Fatalf("Unmarshal() returned error for product: %v", err)
+	}
+
+	product := products[0]
+	if product.ID != 123 || product.ShippingClassID != 4 {
+		t.Errorf("Unexpected product IDs: %+v", product)
+	}
+	if product.Categories[0].ID != 9 || product.Tags[0].ID != 10 || product.Images[0].ID != 11 {
+		t.Errorf("Unexpected category, tag or image IDs: %+v", product)
+	}
+
+	var orders []Order
+	err = json.Unmarshal([]byte(`[{
+		"id": "456",
+		"parent_id": "",
+		"customer_id": "78",
+		"status": "processing",
+		"line_items": [{"id": "1", "product_id": "123", "variation_id": "0", "quantity": 2}]
+	}]`), &orders)
+	if err != nil {
+		t.Fatalf("Unmarshal() returned error for order: %v", err)
+	}
+
+	order := orders[0]
+	if order.ID != 456 || order.ParentID != 0 || order.CustomerID != 78 {
+		t.Errorf("Unexpected order IDs: %+v", order)
+	}
+	item := order.LineItems[0]
+	if item.ID != 1 || item.ProductID != 123 || item.VariationID != 0 {
+		t.Errorf("Unexpected line item IDs: %+v", item)
+	}
+}
@@ -0,0 +1,145 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DecimalSettings mirrors the WooCommerce currency options under
+// WooCommerce > Settings > General
+type DecimalSettings struct {
+	// Decimals is the number of decimals (woocommerce_price_num_decimals)
+	Decimals int32
+	// Rounding is the rounding mode applied when reducing decimals
+	Rounding RoundingMode
+	// DecimalSeparator is the decimal separator (woocommerce_price_decimal_sep)
+	DecimalSeparator string
+	// ThousandSeparator is the thousand separator (woocommerce_price_thousand_sep)
+	ThousandSeparator string
+}
+
+// DefaultDecimalSettings returns the WooCommerce default currency options
+func DefaultDecimalSettings() DecimalSettings {
+	return DecimalSettings{
+		Decimals:          2,
+		Rounding:          RoundHalfUp,
+		DecimalSeparator:  ".",
+		ThousandSeparator: ",",
+	}
+}
+
+// currencyDecimals lists ISO 4217 currencies whose minor unit is not 2
+var currencyDecimals = map[string]int32{
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// CurrencyDecimals returns the number of minor unit digits of an ISO 4217
+// currency code, defaulting to 2
+func CurrencyDecimals(currency string) int32 {
+	if decimals, ok := currencyDecimals[strings.ToUpper(currency)]; ok {
+		return decimals
+	}
+	return 2
+}
+
+// Money is an exact amount in a given currency
+type Money struct {
+	Amount   Decimal `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// NewMoney creates a new Money
+func NewMoney(amount Decimal, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// Add returns m + other. It fails if the currencies differ.
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount.Add(other.Amount), Currency: m.Currency}, nil
+}
+
+// Sub returns m - other. It fails if the currencies differ.
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount.Sub(other.Amount), Currency: m.Currency}, nil
+}
+
+// Mul returns m multiplied by factor, e.g. a unit price by a quantity
+func (m Money) Mul(factor Decimal) Money {
+	return Money{Amount: m.Amount.Mul(factor), Currency: m.Currency}
+}
+
+// Round returns m rounded with the given WooCommerce decimal settings
+func (m Money) Round(settings DecimalSettings) Money {
+	return Money{Amount: m.Amount.Round(settings.Decimals, settings.Rounding), Currency: m.Currency}
+}
+
+// RoundToCurrency returns m rounded to the minor unit of its currency
+func (m Money) RoundToCurrency(mode RoundingMode) Money {
+	return Money{Amount: m.Amount.Round(CurrencyDecimals(m.Currency), mode), Currency: m.Currency}
+}
+
+// Format returns the amount rounded and formatted with the given WooCommerce
+// decimal settings, e.g. "1.234,50" for a European store
+func (m Money) Format(settings DecimalSettings) string {
+	rounded := m.Amount.Round(settings.Decimals, settings.Rounding).String()
+
+	negative := strings.HasPrefix(rounded, "-")
+	rounded = strings.TrimPrefix(rounded, "-")
+	intPart, fracPart, _ := strings.Cut(rounded, ".")
+
+	var grouped strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			grouped.WriteString(settings.ThousandSeparator)
+		}
+		grouped.WriteRune(r)
+	}
+
+	result := grouped.String()
+	if fracPart != "" {
+		result += settings.DecimalSeparator + fracPart
+	}
+	if negative {
+		result = "-" + result
+	}
+	return result
+}
+
+// String returns the amount followed by the currency code, e.g. "19.99 USD"
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Amount.String()
+	}
+	return m.Amount.String() + " " + m.Currency
+}
+
+// checkCurrency returns an error if m and other have different currencies
+func (m Money) checkCurrency(other Money) error {
+	if !strings.EqualFold(m.Currency, other.Currency) {
+		return fmt.Errorf("currency mismatch: %s and %s", m.Currency, other.Currency)
+	}
+	return nil
+}
+
+// Money returns amount in the currency of the order
+func (o *Order) Money(amount Decimal) Money {
+	return NewMoney(amount, o.Currency)
+}
+
+// LineItemsTotal returns the exact sum of the line item totals of the order
+func (o *Order) LineItemsTotal() Money {
+	total := NewDecimalFromInt(0)
+	for _, item := range o.LineItems {
+		total = total.Add(item.Total)
+	}
+	return o.Money(total)
+}
@@ -1,6 +1,9 @@
 package types
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // ProductType represents the type of a product
 type ProductType string
@@ -31,6 +34,33 @@ const (
 	CatalogVisibilityHidden  CatalogVisibility = "hidden"
 )
 
+// StockStatus represents the stock status of a product
+type StockStatus string
+
+const (
+	StockStatusInStock     StockStatus = "instock"
+	StockStatusOutOfStock  StockStatus = "outofstock"
+	StockStatusOnBackorder StockStatus = "onbackorder"
+)
+
+// BackorderStatus represents whether a product allows backorders
+type BackorderStatus string
+
+const (
+	BackordersNo     BackorderStatus = "no"
+	BackordersNotify BackorderStatus = "notify"
+	BackordersYes    BackorderStatus = "yes"
+)
+
+// TaxStatus represents the tax status of a product
+type TaxStatus string
+
+const (
+	TaxStatusTaxable  TaxStatus = "taxable"
+	TaxStatusShipping TaxStatus = "shipping"
+	TaxStatusNone     TaxStatus = "none"
+)
+
 // OrderStatus represents the status of an order
 type OrderStatus string
 
@@ -46,14 +76,14 @@ const (
 
 // Product represents a Dokan product
 type Product struct {
-	ID                int                `json:"id,omitempty"`
+	ID                FlexInt            `json:"id,omitempty"`
 	Name              string             `json:"name"`
 	Slug              string             `json:"slug,omitempty"`
 	Permalink         string             `json:"permalink,omitempty"`
-	DateCreated       *time.Time         `json:"date_created,omitempty"`
-	DateCreatedGMT    *time.Time         `json:"date_created_gmt,omitempty"`
-	DateModified      *time.Time         `json:"date_modified,omitempty"`
-	DateModifiedGMT   *time.Time         `json:"date_modified_gmt,omitempty"`
+	DateCreated       *WPTime            `json:"date_created,omitempty"`
+	DateCreatedGMT    *WPTime            `json:"date_created_gmt,omitempty"`
+	DateModified      *WPTime            `json:"date_modified,omitempty"`
+	DateModifiedGMT   *WPTime            `json:"date_modified_gmt,omitempty"`
 	Type              ProductType        `json:"type"`
 	Status            ProductStatus      `json:"status"`
 	Featured          bool               `json:"featured"`
@@ -61,19 +91,35 @@ type Product struct {
 	Description       string             `json:"description"`
 	ShortDescription  string             `json:"short_description"`
 	SKU               string             `json:"sku"`
-	Price             string             `json:"price,omitempty"`
-	RegularPrice      string             `json:"regular_price"`
-	SalePrice         string             `json:"sale_price,omitempty"`
-	DateOnSaleFrom    *time.Time         `json:"date_on_sale_from,omitempty"`
-	DateOnSaleFromGMT *time.Time         `json:"date_on_sale_from_gmt,omitempty"`
-	DateOnSaleTo      *time.Time         `json:"date_on_sale_to,omitempty"`
-	DateOnSaleToGMT   *time.Time         `json:"date_on_sale_to_gmt,omitempty"`
+	Price             Decimal            `json:"price,omitzero"`
+	RegularPrice      Decimal            `json:"regular_price"`
+	SalePrice         Decimal            `json:"sale_price,omitzero"`
+	DateOnSaleFrom    *WPTime            `json:"date_on_sale_from,omitempty"`
+	DateOnSaleFromGMT *WPTime            `json:"date_on_sale_from_gmt,omitempty"`
+	DateOnSaleTo      *WPTime            `json:"date_on_sale_to,omitempty"`
+	DateOnSaleToGMT   *WPTime            `json:"date_on_sale_to_gmt,omitempty"`
 	PriceHTML         string             `json:"price_html,omitempty"`
 	OnSale            bool               `json:"on_sale,omitempty"`
 	Purchasable       bool               `json:"purchasable,omitempty"`
-	TotalSales        int                `json:"total_sales,omitempty"`
+	TotalSales        FlexInt            `json:"total_sales,omitempty"`
 	Virtual           bool               `json:"virtual"`
 	Downloadable      bool               `json:"downloadable"`
+	TaxStatus         TaxStatus          `json:"tax_status,omitempty"`
+	TaxClass          string             `json:"tax_class,omitempty"`
+	ManageStock       bool               `json:"manage_stock"`
+	StockQuantity     *FlexInt           `json:"stock_quantity,omitempty"`
+	StockStatus       StockStatus        `json:"stock_status,omitempty"`
+	Backorders        BackorderStatus    `json:"backorders,omitempty"`
+	BackordersAllowed bool               `json:"backorders_allowed,omitempty"`
+	Backordered       bool               `json:"backordered,omitempty"`
+	LowStockAmount    *FlexInt           `json:"low_stock_amount,omitempty"`
+	SoldIndividually  bool               `json:"sold_individually"`
+	Weight            string             `json:"weight,omitempty"`
+	Dimensions        *ProductDimensions `json:"dimensions,omitempty"`
+	ShippingRequired  bool               `json:"shipping_required,omitempty"`
+	ShippingTaxable   bool               `json:"shipping_taxable,omitempty"`
+	ShippingClass     string             `json:"shipping_class,omitempty"`
+	ShippingClassID   FlexInt            `json:"shipping_class_id,omitempty"`
 	Categories        []ProductCategory  `json:"categories,omitempty"`
 	Tags              []ProductTag       `json:"tags,omitempty"`
 	Images            []ProductImage     `json:"images,omitempty"`
@@ -85,32 +131,69 @@ type Product struct {
 	MetaData          []MetaData         `json:"meta_data,omitempty"`
 }
 
+// UnmarshalJSON implements json.Unmarshaler, pairing each local date with
+// its _gmt sibling
+func (p *Product) UnmarshalJSON(data []byte) error {
+	type product Product
+	if err := json.Unmarshal(data, (*product)(p)); err != nil {
+		return err
+	}
+
+	PairWPTime(p.DateCreated, p.DateCreatedGMT)
+	PairWPTime(p.DateModified, p.DateModifiedGMT)
+	PairWPTime(p.DateOnSaleFrom, p.DateOnSaleFromGMT)
+	PairWPTime(p.DateOnSaleTo, p.DateOnSaleToGMT)
+	return nil
+}
+
+// SetStockQuantity enables stock management and sets the stock quantity
+func (p *Product) SetStockQuantity(quantity int) {
+	q := FlexInt(quantity)
+	p.ManageStock = true
+	p.StockQuantity = &q
+}
+
+// Stock returns the stock quantity, or zero when it is not managed
+func (p *Product) Stock() int {
+	if p.StockQuantity == nil {
+		return 0
+	}
+	return int(*p.StockQuantity)
+}
+
+// ProductDimensions represents the dimensions of a product
+type ProductDimensions struct {
+	Length string `json:"length"`
+	Width  string `json:"width"`
+	Height string `json:"height"`
+}
+
 // ProductCategory represents a product category
 type ProductCategory struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
-	Slug string `json:"slug"`
+	ID   FlexInt `json:"id"`
+	Name string  `json:"name"`
+	Slug string  `json:"slug"`
 }
 
 // ProductTag represents a product tag
 type ProductTag struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
-	Slug string `json:"slug"`
+	ID   FlexInt `json:"id"`
+	Name string  `json:"name"`
+	Slug string  `json:"slug"`
 }
 
 // ProductImage represents a product image
 type ProductImage struct {
-	ID       int    `json:"id,omitempty"`
-	Src      string `json:"src"`
-	Name     string `json:"name,omitempty"`
-	Alt      string `json:"alt,omitempty"`
-	Position int    `json:"position,omitempty"`
+	ID       FlexInt `json:"id,omitempty"`
+	Src      string  `json:"src"`
+	Name     string  `json:"name,omitempty"`
+	Alt      string  `json:"alt,omitempty"`
+	Position int     `json:"position,omitempty"`
 }
 
 // ProductAttribute represents a product attribute
 type ProductAttribute struct {
-	ID        int      `json:"id,omitempty"`
+	ID        FlexInt  `json:"id,omitempty"`
 	Name      string   `json:"name"`
 	Position  int      `json:"position,omitempty"`
 	Visible   bool     `json:"visible"`
@@ -120,27 +203,27 @@ type ProductAttribute struct {
 
 // Order represents a Dokan order
 type Order struct {
-	ID                 int            `json:"id,omitempty"`
-	ParentID           int            `json:"parent_id,omitempty"`
+	ID                 FlexInt        `json:"id,omitempty"`
+	ParentID           FlexInt        `json:"parent_id,omitempty"`
 	Number             string         `json:"number,omitempty"`
 	OrderKey           string         `json:"order_key,omitempty"`
 	CreatedVia         string         `json:"created_via,omitempty"`
 	Version            string         `json:"version,omitempty"`
 	Status             OrderStatus    `json:"status"`
 	Currency           string         `json:"currency"`
-	DateCreated        *time.Time     `json:"date_created,omitempty"`
-	DateCreatedGMT     *time.Time     `json:"date_created_gmt,omitempty"`
-	DateModified       *time.Time     `json:"date_modified,omitempty"`
-	DateModifiedGMT    *time.Time     `json:"date_modified_gmt,omitempty"`
-	DiscountTotal      string         `json:"discount_total,omitempty"`
-	DiscountTax        string         `json:"discount_tax,omitempty"`
-	ShippingTotal      string         `json:"shipping_total,omitempty"`
-	ShippingTax        string         `json:"shipping_tax,omitempty"`
-	CartTax            string         `json:"cart_tax,omitempty"`
-	Total              string         `json:"total,omitempty"`
-	TotalTax           string         `json:"total_tax,omitempty"`
+	DateCreated        *WPTime        `json:"date_created,omitempty"`
+	DateCreatedGMT     *WPTime        `json:"date_created_gmt,omitempty"`
+	DateModified       *WPTime        `json:"date_modified,omitempty"`
+	DateModifiedGMT    *WPTime        `json:"date_modified_gmt,omitempty"`
+	DiscountTotal      Decimal        `json:"discount_total,omitzero"`
+	DiscountTax        Decimal        `json:"discount_tax,omitzero"`
+	ShippingTotal      Decimal        `json:"shipping_total,omitzero"`
+	ShippingTax        Decimal        `json:"shipping_tax,omitzero"`
+	CartTax            Decimal        `json:"cart_tax,omitzero"`
+	Total              Decimal        `json:"total,omitzero"`
+	TotalTax           Decimal        `json:"total_tax,omitzero"`
 	PricesIncludeTax   bool           `json:"prices_include_tax,omitempty"`
-	CustomerID         int            `json:"customer_id,omitempty"`
+	CustomerID         FlexInt        `json:"customer_id,omitempty"`
 	CustomerIPAddress  string         `json:"customer_ip_address,omitempty"`
 	CustomerUserAgent  string         `json:"customer_user_agent,omitempty"`
 	CustomerNote       string         `json:"customer_note,omitempty"`
@@ -149,10 +232,10 @@ type Order struct {
 	PaymentMethod      string         `json:"payment_method,omitempty"`
 	PaymentMethodTitle string         `json:"payment_method_title,omitempty"`
 	TransactionID      string         `json:"transaction_id,omitempty"`
-	DatePaid           *time.Time     `json:"date_paid,omitempty"`
-	DatePaidGMT        *time.Time     `json:"date_paid_gmt,omitempty"`
-	DateCompleted      *time.Time     `json:"date_completed,omitempty"`
-	DateCompletedGMT   *time.Time     `json:"date_completed_gmt,omitempty"`
+	DatePaid           *WPTime        `json:"date_paid,omitempty"`
+	DatePaidGMT        *WPTime        `json:"date_paid_gmt,omitempty"`
+	DateCompleted      *WPTime        `json:"date_completed,omitempty"`
+	DateCompletedGMT   *WPTime        `json:"date_completed_gmt,omitempty"`
 	CartHash           string         `json:"cart_hash,omitempty"`
 	LineItems          []LineItem     `json:"line_items,omitempty"`
 	TaxLines           []TaxLine      `json:"tax_lines,omitempty"`
@@ -163,6 +246,21 @@ type Order struct {
 	MetaData           []MetaData     `json:"meta_data,omitempty"`
 }
 
+// UnmarshalJSON implements json.Unmarshaler, pairing each local date with
+// its _gmt sibling
+func (o *Order) UnmarshalJSON(data []byte) error {
+	type order Order
+	if err := json.Unmarshal(data, (*order)(o)); err != nil {
+		return err
+	}
+
+	PairWPTime(o.DateCreated, o.DateCreatedGMT)
+	PairWPTime(o.DateModified, o.DateModifiedGMT)
+	PairWPTime(o.DatePaid, o.DatePaidGMT)
+	PairWPTime(o.DateCompleted, o.DateCompletedGMT)
+	return nil
+}
+
 // Address represents a billing or shipping address
 type Address struct {
 	FirstName string `json:"first_name"`
@@ -180,82 +278,82 @@ type Address struct {
 
 // LineItem represents an order line item
 type LineItem struct {
-	ID          int        `json:"id,omitempty"`
+	ID          FlexInt    `json:"id,omitempty"`
 	Name        string     `json:"name"`
-	ProductID   int        `json:"product_id"`
-	VariationID int        `json:"variation_id,omitempty"`
+	ProductID   FlexInt    `json:"product_id"`
+	VariationID FlexInt    `json:"variation_id,omitempty"`
 	Quantity    int        `json:"quantity"`
 	TaxClass    string     `json:"tax_class,omitempty"`
-	Subtotal    string     `json:"subtotal"`
-	SubtotalTax string     `json:"subtotal_tax"`
-	Total       string     `json:"total"`
-	TotalTax    string     `json:"total_tax"`
+	Subtotal    Decimal    `json:"subtotal"`
+	SubtotalTax Decimal    `json:"subtotal_tax"`
+	Total       Decimal    `json:"total"`
+	TotalTax    Decimal    `json:"total_tax"`
 	Taxes       []TaxLine  `json:"taxes,omitempty"`
 	MetaData    []MetaData `json:"meta_data,omitempty"`
 	SKU         string     `json:"sku,omitempty"`
-	Price       float64    `json:"price,omitempty"`
+	Price       Decimal    `json:"price,omitzero"`
 }
 
 // TaxLine represents a tax line
 type TaxLine struct {
-	ID               int        `json:"id,omitempty"`
+	ID               FlexInt    `json:"id,omitempty"`
 	RateCode         string     `json:"rate_code"`
-	RateID           int        `json:"rate_id"`
+	RateID           FlexInt    `json:"rate_id"`
 	Label            string     `json:"label"`
 	Compound         bool       `json:"compound"`
-	TaxTotal         string     `json:"tax_total"`
-	ShippingTaxTotal string     `json:"shipping_tax_total"`
+	TaxTotal         Decimal    `json:"tax_total"`
+	ShippingTaxTotal Decimal    `json:"shipping_tax_total"`
 	MetaData         []MetaData `json:"meta_data,omitempty"`
 }
 
 // ShippingLine represents a shipping line
 type ShippingLine struct {
-	ID          int        `json:"id,omitempty"`
+	ID          FlexInt    `json:"id,omitempty"`
 	MethodTitle string     `json:"method_title"`
 	MethodID    string     `json:"method_id"`
-	Total       string     `json:"total"`
-	TotalTax    string     `json:"total_tax"`
+	Total       Decimal    `json:"total"`
+	TotalTax    Decimal    `json:"total_tax"`
 	Taxes       []TaxLine  `json:"taxes,omitempty"`
 	MetaData    []MetaData `json:"meta_data,omitempty"`
 }
 
 // FeeLine represents a fee line
 type FeeLine struct {
-	ID        int        `json:"id,omitempty"`
+	ID        FlexInt    `json:"id,omitempty"`
 	Name      string     `json:"name"`
 	TaxClass  string     `json:"tax_class,omitempty"`
 	TaxStatus string     `json:"tax_status"`
-	Total     string     `json:"total"`
-	TotalTax  string     `json:"total_tax"`
+	Total     Decimal    `json:"total"`
+	TotalTax  Decimal    `json:"total_tax"`
 	Taxes     []TaxLine  `json:"taxes,omitempty"`
 	MetaData  []MetaData `json:"meta_data,omitempty"`
 }
 
 // CouponLine represents a coupon line
 type CouponLine struct {
-	ID          int        `json:"id,omitempty"`
+	ID          FlexInt    `json:"id,omitempty"`
 	Code        string     `json:"code"`
-	Discount    string     `json:"discount"`
-	DiscountTax string     `json:"discount_tax"`
+	Discount    Decimal    `json:"discount"`
+	DiscountTax Decimal    `json:"discount_tax"`
 	MetaData    []MetaData `json:"meta_data,omitempty"`
 }
 
 // Refund represents a refund
 type Refund struct {
-	ID     int    `json:"id"`
-	Reason string `json:"reason,omitempty"`
-	Total  string `json:"total"`
+	ID     FlexInt `json:"id"`
+	Reason string  `json:"reason,omitempty"`
+	Total  Decimal `json:"total"`
 }
 
 // Store represents a Dokan store
 type Store struct {
-	ID             int                          `json:"id"`
+	ID             FlexInt                      `json:"id"`
 	StoreName      string                       `json:"store_name"`
 	FirstName      string                       `json:"first_name"`
 	LastName       string                       `json:"last_name"`
 	Email          string                       `json:"email"`
 	Phone          string                       `json:"phone,omitempty"`
-	ShowEmail      bool                         `json:"show_email,omitempty"`
+	ShowEmail      FlexBool                     `json:"show_email,omitempty"`
 	Address        *Address                     `json:"address,omitempty"`
 	Location       string                       `json:"location,omitempty"`
 	Banner         string                       `json:"banner,omitempty"`
@@ -264,23 +362,23 @@ type Store struct {
 	ShopURL        string                       `json:"shop_url,omitempty"`
 	ProductsURL    string                       `json:"products_url,omitempty"`
 	TocsURL        string                       `json:"tocs_url,omitempty"`
-	Featured       bool                         `json:"featured,omitempty"`
+	Featured       FlexBool                     `json:"featured,omitempty"`
 	Rating         *Rating                      `json:"rating,omitempty"`
-	Enabled        bool                         `json:"enabled,omitempty"`
-	Registered     string                       `json:"registered,omitempty"`
+	Enabled        FlexBool                     `json:"enabled,omitempty"`
+	Registered     *WPTime                      `json:"registered,omitempty"`
 	PaymentMethods map[string]map[string]string `json:"payment,omitempty"`
 	Social         map[string]string            `json:"social,omitempty"`
 }
 
 // Rating represents store rating information
 type Rating struct {
-	Rating string `json:"rating"`
-	Count  int    `json:"count"`
+	Rating FlexFloat `json:"rating"`
+	Count  FlexInt   `json:"count"`
 }
 
 // MetaData represents metadata
 type MetaData struct {
-	ID    int         `json:"id,omitempty"`
+	ID    FlexInt     `json:"id,omitempty"`
 	Key   string      `json:"key"`
 	Value interface{} `json:"value"`
 }
@@ -312,7 +410,7 @@ type ProductListParams struct {
 	Tag         []int           `url:"tag,omitempty"`
 	MinPrice    *float64        `url:"min_price,omitempty"`
 	MaxPrice    *float64        `url:"max_price,omitempty"`
-	StockStatus string          `url:"stock_status,omitempty"`
+	StockStatus StockStatus     `url:"stock_status,omitempty"`
 	SKU         string          `url:"sku,omitempty"`
 }
 
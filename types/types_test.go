@@ -0,0 +1,68 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProduct_InventoryFields(t *testing.T) {
+	data := []byte(`{
+		"id": 10,
+		"name": "Mug",
+		"manage_stock": true,
+		"stock_quantity": "7",
+		"stock_status": "instock",
+		"backorders": "notify",
+		"low_stock_amount": null,
+		"weight": "0.4",
+		"dimensions": {"length": "10", "width": "8", "height": "12"},
+		"shipping_class": "fragile",
+		"tax_status": "taxable"
+	}`)
+
+	var product Product
+	if err := json.Unmarshal(data, &product); err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+
+	if !product.ManageStock || product.Stock() != 7 {
+		t.Errorf("Expected managed stock of 7, got %v/%d", product.ManageStock, product.Stock())
+	}
+	if product.StockStatus != StockStatusInStock {
+		t.Errorf("Expected stock status %s, got %s", StockStatusInStock, product.StockStatus)
+	}
+	if product.Backorders != BackordersNotify {
+		t.Errorf("Expected backorders %s, got %s", BackordersNotify, product.Backorders)
+	}
+	if product.LowStockAmount != nil {
+		t.Errorf("Expected nil low stock amount, got %v", *product.LowStockAmount)
+	}
+	if product.Dimensions == nil || product.Dimensions.Height != "12" {
+		t.Errorf("Unexpected dimensions: %+v", product.Dimensions)
+	}
+	if product.TaxStatus != TaxStatusTaxable {
+		t.Errorf("Expected tax status %s, got %s", TaxStatusTaxable, product.TaxStatus)
+	}
+}
+
+func TestProduct_SetStockQuantity(t *testing.T) {
+	product := Product{Name: "Mug"}
+	product.SetStockQuantity(0)
+
+	out, err := json.Marshal(product)
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(out, &fields); err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+
+	if fields["manage_stock"] != true {
+		t.Errorf("Expected manage_stock true, got %v", fields["manage_stock"])
+	}
+	if qty, ok := fields["stock_quantity"]; !ok || qty != float64(0) {
+		t.Errorf("Expected stock_quantity 0 to be sent, got %v", qty)
+	}
+}
@@ -0,0 +1,115 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ProductVariation represents a variation of a variable product
+type ProductVariation struct {
+	ID                FlexInt              `json:"id,omitempty"`
+	DateCreated       *WPTime              `json:"date_created,omitempty"`
+	DateCreatedGMT    *WPTime              `json:"date_created_gmt,omitempty"`
+	DateModified      *WPTime              `json:"date_modified,omitempty"`
+	DateModifiedGMT   *WPTime              `json:"date_modified_gmt,omitempty"`
+	Description       string               `json:"description,omitempty"`
+	Permalink         string               `json:"permalink,omitempty"`
+	SKU               string               `json:"sku,omitempty"`
+	Price             Decimal              `json:"price,omitzero"`
+	RegularPrice      Decimal              `json:"regular_price,omitzero"`
+	SalePrice         Decimal              `json:"sale_price,omitzero"`
+	DateOnSaleFrom    *WPTime              `json:"date_on_sale_from,omitempty"`
+	DateOnSaleFromGMT *WPTime              `json:"date_on_sale_from_gmt,omitempty"`
+	DateOnSaleTo      *WPTime              `json:"date_on_sale_to,omitempty"`
+	DateOnSaleToGMT   *WPTime              `json:"date_on_sale_to_gmt,omitempty"`
+	OnSale            bool                 `json:"on_sale,omitempty"`
+	Status            ProductStatus        `json:"status,omitempty"`
+	Purchasable       bool                 `json:"purchasable,omitempty"`
+	Virtual           bool                 `json:"virtual"`
+	Downloadable      bool                 `json:"downloadable"`
+	TaxStatus         TaxStatus            `json:"tax_status,omitempty"`
+	TaxClass          string               `json:"tax_class,omitempty"`
+	ManageStock       bool                 `json:"manage_stock"`
+	StockQuantity     *FlexInt             `json:"stock_quantity,omitempty"`
+	StockStatus       StockStatus          `json:"stock_status,omitempty"`
+	Backorders        BackorderStatus      `json:"backorders,omitempty"`
+	BackordersAllowed bool                 `json:"backorders_allowed,omitempty"`
+	Backordered       bool                 `json:"backordered,omitempty"`
+	LowStockAmount    *FlexInt             `json:"low_stock_amount,omitempty"`
+	Weight            string               `json:"weight,omitempty"`
+	Dimensions        *ProductDimensions   `json:"dimensions,omitempty"`
+	ShippingClass     string               `json:"shipping_class,omitempty"`
+	ShippingClassID   FlexInt              `json:"shipping_class_id,omitempty"`
+	Image             *ProductImage        `json:"image,omitempty"`
+	Attributes        []VariationAttribute `json:"attributes,omitempty"`
+	MenuOrder         int                  `json:"menu_order,omitempty"`
+	MetaData          []MetaData           `json:"meta_data,omitempty"`
+
+	// StockManagedByParent is set when the API reports manage_stock as
+	// "parent", meaning the stock is managed at the parent product level
+	StockManagedByParent bool `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, pairing each local date with
+// its _gmt sibling and accepting "parent" for manage_stock
+func (v *ProductVariation) UnmarshalJSON(data []byte) error {
+	type variation ProductVariation
+	aux := struct {
+		*variation
+		ManageStock json.RawMessage `json:"manage_stock"`
+	}{variation: (*variation)(v)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	v.ManageStock = false
+	v.StockManagedByParent = false
+	if bytes.Equal(aux.ManageStock, []byte(`"parent"`)) {
+		v.StockManagedByParent = true
+	} else if len(aux.ManageStock) > 0 {
+		var manageStock FlexBool
+		if err := json.Unmarshal(aux.ManageStock, &manageStock); err != nil {
+			return err
+		}
+		v.ManageStock = bool(manageStock)
+	}
+
+	PairWPTime(v.DateCreated, v.DateCreatedGMT)
+	PairWPTime(v.DateModified, v.DateModifiedGMT)
+	PairWPTime(v.DateOnSaleFrom, v.DateOnSaleFromGMT)
+	PairWPTime(v.DateOnSaleTo, v.DateOnSaleToGMT)
+	return nil
+}
+
+// SetStockQuantity enables stock management on the variation and sets the
+// stock quantity
+func (v *ProductVariation) SetStockQuantity(quantity int) {
+	q := FlexInt(quantity)
+	v.ManageStock = true
+	v.StockQuantity = &q
+}
+
+// Stock returns the stock quantity, or zero when it is not managed
+func (v *ProductVariation) Stock() int {
+	if v.StockQuantity == nil {
+		return 0
+	}
+	return int(*v.StockQuantity)
+}
+
+// VariationAttribute represents the attribute option selected by a variation
+type VariationAttribute struct {
+	ID     FlexInt `json:"id,omitempty"`
+	Name   string  `json:"name"`
+	Option string  `json:"option"`
+}
+
+// VariationListParams represents parameters for listing product variations
+type VariationListParams struct {
+	ListParams
+	SKU         string          `url:"sku,omitempty"`
+	Status      []ProductStatus `url:"status,omitempty"`
+	StockStatus StockStatus     `url:"stock_status,omitempty"`
+	OnSale      *bool           `url:"on_sale,omitempty"`
+}
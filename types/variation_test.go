@@ -0,0 +1,61 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProductVariation_UnmarshalManageStock(t *testing.T) {
+	tests := []struct {
+		input       string
+		manageStock bool
+		parent      bool
+	}{
+		{`{"manage_stock": true}`, true, false},
+		{`{"manage_stock": false}`, false, false},
+		{`{"manage_stock": "parent"}`, false, true},
+		{`{}`, false, false},
+	}
+
+	for _, tt := range tests {
+		var v ProductVariation
+		if err := json.Unmarshal([]byte(tt.input), &v); err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %v", tt.input, err)
+			continue
+		}
+		if v.ManageStock != tt.manageStock {
+			t.Errorf("Unmarshal(%s): expected ManageStock %v, got %v", tt.input, tt.manageStock, v.ManageStock)
+		}
+		if v.StockManagedByParent != tt.parent {
+			t.Errorf("Unmarshal(%s): expected StockManagedByParent %v, got %v", tt.input, tt.parent, v.StockManagedByParent)
+		}
+	}
+}
+
+func TestProductVariation_Unmarshal(t *testing.T) {
+	data := []byte(`{
+		"id": 42,
+		"sku": "SHIRT-RED-M",
+		"regular_price": "19.90",
+		"stock_quantity": "7",
+		"attributes": [{"id": 1, "name": "Color", "option": "Red"}]
+	}`)
+
+	var v ProductVariation
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+
+	if v.ID != 42 || v.SKU != "SHIRT-RED-M" {
+		t.Errorf("Unexpected variation: %+v", v)
+	}
+	if v.RegularPrice.String() != "19.90" {
+		t.Errorf("Expected regular price 19.90, got %s", v.RegularPrice)
+	}
+	if v.Stock() != 7 {
+		t.Errorf("Expected stock 7, got %d", v.Stock())
+	}
+	if len(v.Attributes) != 1 || v.Attributes[0].Option != "Red" {
+		t.Errorf("Unexpected attributes: %+v", v.Attributes)
+	}
+}
@@ -0,0 +1,118 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WPTimeLayout is the layout used by the WordPress REST API for dates
+const WPTimeLayout = "2006-01-02T15:04:05"
+
+// wpTimeLayouts lists the layouts accepted when parsing WordPress dates
+// without a zone, which are parsed as UTC
+var wpTimeLayouts = []string{
+	WPTimeLayout,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// WPTime represents a date returned by the WordPress REST API.
+//
+// WooCommerce and Dokan send dates such as "2024-03-22T16:28:02" without a
+// timezone: the plain field (e.g. date_created) is in the site timezone and
+// its _gmt sibling (e.g. date_created_gmt) is in UTC. WPTime parses both, as
+// well as RFC 3339 values, empty strings, null and "0000-00-00 00:00:00",
+// which decode to the zero time. When a type carries both fields, the local
+// value is paired with its GMT sibling after decoding so that it is placed in
+// the site's actual UTC offset, unless it carries an offset of its own.
+type WPTime struct {
+	time.Time
+
+	// zoned is set when the time was parsed from a value with an offset
+	zoned bool
+}
+
+// NewWPTime creates a new WPTime from t
+func NewWPTime(t time.Time) *WPTime {
+	return &WPTime{Time: t}
+}
+
+// ParseWPTime parses a date in any of the formats returned by WordPress
+func ParseWPTime(value string) (WPTime, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.HasPrefix(value, "0000-00-00") {
+		return WPTime{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return WPTime{Time: t, zoned: true}, nil
+	}
+	for _, layout := range wpTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return WPTime{Time: t}, nil
+		}
+	}
+
+	return WPTime{}, fmt.Errorf("invalid WordPress date: %q", value)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *WPTime) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid WordPress date: %s", data)
+	}
+
+	parsed, err := ParseWPTime(value)
+	if err != nil {
+		return err
+	}
+
+	*t = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The time is written in the layout
+// accepted by the API, using the wall clock of its location, or as null
+// when it is zero.
+func (t WPTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Format(WPTimeLayout))
+}
+
+// String returns the time in the layout used by the API
+func (t WPTime) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(WPTimeLayout)
+}
+
+// PairWPTime places a local site time in the UTC offset derived from its GMT
+// sibling, e.g. date_created and date_created_gmt. It only applies when local
+// was parsed from a value without an offset; one that has an offset is left
+// as is. A GMT value without an offset is taken to be in UTC.
+func PairWPTime(local, gmt *WPTime) {
+	if local == nil || gmt == nil || local.IsZero() || gmt.IsZero() || local.zoned {
+		return
+	}
+
+	offset := local.Sub(gmt.Time).Round(time.Minute)
+	if offset < -14*time.Hour || offset > 14*time.Hour {
+		return
+	}
+
+	zone := time.FixedZone("", int(offset.Seconds()))
+	local.Time = gmt.In(zone)
+}
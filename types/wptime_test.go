@@ -0,0 +1,131 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestWPTime_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Time
+	}{
+		{"without timezone", `"2024-03-22T16:28:02"`, time.Date(2024, 3, 22, 16, 28, 2, 0, time.UTC)},
+		{"with space separator", `"2024-03-22 16:28:02"`, time.Date(2024, 3, 22, 16, 28, 2, 0, time.UTC)},
+		{"RFC 3339", `"2024-03-22T16:28:02Z"`, time.Date(2024, 3, 22, 16, 28, 2, 0, time.UTC)},
+		{"date only", `"2024-03-22"`, time.Date(2024, 3, 22, 0, 0, 0, 0, time.UTC)},
+		{"empty string", `""`, time.Time{}},
+		{"null", `null`, time.Time{}},
+		{"zero date", `"0000-00-00 00:00:00"`, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var wt WPTime
+			if err := json.Unmarshal([]byte(tt.input), &wt); err != nil {
+				t.Fatalf("Unmarshal() returned error: %v", err)
+			}
+			if !wt.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, wt.Time)
+			}
+		})
+	}
+}
+
+func TestWPTime_UnmarshalJSON_Invalid(t *testing.T) {
+	var wt WPTime
+	if err := json.Unmarshal([]byte(`"yesterday"`), &wt); err == nil {
+		t.Error("Unmarshal() should return error for an invalid date")
+	}
+}
+
+func TestWPTime_MarshalJSON(t *testing.T) {
+	zone := time.FixedZone("", -3*60*60)
+	wt := WPTime{Time: time.Date(2024, 3, 22, 16, 28, 2, 0, zone)}
+
+	data, err := json.Marshal(wt)
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+	if string(data) != `"2024-03-22T16:28:02"` {
+		t.Errorf("Expected \"2024-03-22T16:28:02\", got %s", data)
+	}
+
+	data, err = json.Marshal(WPTime{})
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+	if string(data) != "null" {
+		t.Errorf("Expected null for zero time, got %s", data)
+	}
+}
+
+func TestProduct_UnmarshalJSON_PairsGMTDates(t *testing.T) {
+	data := []byte(`{
+		"id": 1,
+		"name": "Shirt",
+		"date_created": "2024-03-22T13:28:02",
+		"date_created_gmt": "2024-03-22T16:28:02",
+		"date_modified": null,
+		"date_on_sale_from": ""
+	}`)
+
+	var product Product
+	if err := json.Unmarshal(data, &product); err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+
+	if product.DateCreated == nil {
+		t.Fatal("DateCreated should be set")
+	}
+	if !product.DateCreated.Equal(product.DateCreatedGMT.Time) {
+		t.Errorf("Expected local and GMT dates to be the same instant, got %v and %v", product.DateCreated.Time, product.DateCreatedGMT.Time)
+	}
+	if _, offset := product.DateCreated.Zone(); offset != -3*60*60 {
+		t.Errorf("Expected UTC offset -3h, got %ds", offset)
+	}
+	if product.DateCreated.String() != "2024-03-22T13:28:02" {
+		t.Errorf("Expected local wall clock to be preserved, got %s", product.DateCreated)
+	}
+	if product.DateModified != nil {
+		t.Errorf("Expected nil DateModified, got %v", product.DateModified)
+	}
+	if product.DateOnSaleFrom == nil || !product.DateOnSaleFrom.IsZero() {
+		t.Errorf("Expected zero DateOnSaleFrom, got %v", product.DateOnSaleFrom)
+	}
+}
+
+func TestProduct_UnmarshalJSON_KeepsExplicitOffsets(t *testing.T) {
+	tests := []struct {
+		name     string
+		created  string
+		gmt      string
+		offset   int
+		expected time.Time
+	}{
+		{"+02:00 with Z sibling", "2024-03-22T18:28:02+02:00", "2024-03-22T16:28:02Z", 2 * 60 * 60, time.Date(2024, 3, 22, 16, 28, 2, 0, time.UTC)},
+		{"+02:00 with disagreeing sibling", "2024-03-22T18:28:02+02:00", "2024-03-22T19:28:02", 2 * 60 * 60, time.Date(2024, 3, 22, 16, 28, 2, 0, time.UTC)},
+		{"Z with zone-less sibling", "2024-03-22T16:28:02Z", "2024-03-22T14:28:02", 0, time.Date(2024, 3, 22, 16, 28, 2, 0, time.UTC)},
+		{"zone-less with Z sibling", "2024-03-22T18:28:02", "2024-03-22T16:28:02Z", 2 * 60 * 60, time.Date(2024, 3, 22, 16, 28, 2, 0, time.UTC)},
+		{"zone-less with +02:00 sibling", "2024-03-22T18:28:02", "2024-03-22T18:28:02+02:00", 2 * 60 * 60, time.Date(2024, 3, 22, 16, 28, 2, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte(`{"id": 1, "date_created": "` + tt.created + `", "date_created_gmt": "` + tt.gmt + `"}`)
+			var product Product
+			if err := json.Unmarshal(data, &product); err != nil {
+				t.Fatalf("Unmarshal() returned error: %v", err)
+			}
+
+			if _, offset := product.DateCreated.Zone(); offset != tt.offset {
+				t.Errorf("Expected UTC offset %ds, got %ds", tt.offset, offset)
+			}
+			if !product.DateCreated.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, product.DateCreated.Time)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/errors"
+)
+
+// RetryDecision is the outcome of classifying a failed request
+type RetryDecision int
+
+const (
+	// RetryAbort returns the error without retrying
+	RetryAbort RetryDecision = iota
+	// RetrySafe retries the request
+	RetrySafe
+	// RetryIfAbsent retries the request only if RequestOptions.Dedupe
+	// reports that the failed attempt did not create the resource. Requests
+	// without a Dedupe function are not retried.
+	RetryIfAbsent
+)
+
+// RetryPolicy decides whether a failed request may be retried and how long
+// to wait before doing so
+type RetryPolicy interface {
+	// ShouldRetry classifies err, returned by a request with the given
+	// HTTP method
+	ShouldRetry(method string, err error) RetryDecision
+	// Backoff returns the delay before retry number attempt (starting at
+	// 1), given the previous delay. It is not called when the server
+	// requested a delay through Retry-After.
+	Backoff(attempt int, prev time.Duration) time.Duration
+}
+
+// DefaultRetryPolicy retries network errors, 5xx responses and rate limiting,
+// waiting exponentially longer between attempts.
+//
+// Idempotent methods are retried whenever the error is retryable.
+// Non-idempotent methods such as POST are retried unconditionally only when
+// the server cannot have committed the request: the connection failed before
+// the request was written, or the server rejected it with 429. Any other
+// failure may have created the resource, so they are retried only after
+// Dedupe confirms it does not exist.
+//
+// Authentication, validation and not found errors are never retried, nor
+// are requests rejected by an open circuit breaker.
+type DefaultRetryPolicy struct {
+	// BaseDelay, MaxDelay, Multiplier and Jitter configure the backoff as
+	// in RetryConfig. Zero values use those of DefaultRetryConfig.
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	Multiplier float64
+	Jitter     Jitter
+}
+
+// ShouldRetry implements RetryPolicy
+func (DefaultRetryPolicy) ShouldRetry(method string, err error) RetryDecision {
+	var (
+		authErr       *errors.AuthenticationError
+		validationErr *errors.ValidationError
+		notFoundErr   *errors.NotFoundError
+	)
+	if stderrors.As(err, &authErr) || stderrors.As(err, &validationErr) || stderrors.As(err, &notFoundErr) {
+		return RetryAbort
+	}
+	if stderrors.Is(err, errors.ErrCircuitOpen) {
+		return RetryAbort
+	}
+
+	var networkErr *errors.NetworkError
+	if stderrors.As(err, &networkErr) && !networkErr.RequestWritten {
+		return RetrySafe
+	}
+
+	var rateLimitErr *errors.RateLimitError
+	if stderrors.As(err, &rateLimitErr) {
+		return RetrySafe
+	}
+
+	var dokanErr *errors.DokanError
+	if stderrors.As(err, &dokanErr) {
+		switch {
+		case dokanErr.StatusCode == http.StatusTooManyRequests:
+			return RetrySafe
+		case dokanErr.StatusCode >= 400 && dokanErr.StatusCode < 500:
+			// Don't retry client errors
+			return RetryAbort
+		}
+	}
+
+	if IsIdempotent(method) {
+		return RetrySafe
+	}
+	return RetryIfAbsent
+}
+
+// Backoff implements RetryPolicy
+func (p DefaultRetryPolicy) Backoff(attempt int, prev time.Duration) time.Duration {
+	config := DefaultRetryConfig()
+	if p.BaseDelay > 0 {
+		config.BaseDelay = p.BaseDelay
+	}
+	if p.MaxDelay > 0 {
+		config.MaxDelay = p.MaxDelay
+	}
+	if p.Multiplier > 0 {
+		config.Multiplier = p.Multiplier
+	}
+	config.Jitter = p.Jitter
+	return config.Backoff(attempt, prev)
+}
+
+// NoRetryPolicy never retries
+type NoRetryPolicy struct{}
+
+// ShouldRetry implements RetryPolicy
+func (NoRetryPolicy) ShouldRetry(method string, err error) RetryDecision {
+	return RetryAbort
+}
+
+// Backoff implements RetryPolicy
+func (NoRetryPolicy) Backoff(attempt int, prev time.Duration) time.Duration {
+	return 0
+}
+
+// configPolicy classifies errors like DefaultRetryPolicy and waits the
+// backoff delays of a RetryConfig. It is used when RetryConfig.Policy is nil.
+type configPolicy struct {
+	DefaultRetryPolicy
+	config RetryConfig
+}
+
+// Backoff implements RetryPolicy
+func (p configPolicy) Backoff(attempt int, prev time.Duration) time.Duration {
+	return p.config.Backoff(attempt, prev)
+}
+
+// IsIdempotent reports whether requests with the given HTTP method can be
+// repeated without changing the result, as defined by RFC 9110
+func IsIdempotent(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// RetryBudget limits retries across every request that shares it, so that a
+// failing backend is not flooded with retries from many goroutines at once.
+//
+// Each retry withdraws one token and each successful request deposits Ratio
+// tokens, up to MaxTokens. Retries are skipped while fewer than one token is
+// left, which keeps retries at about Ratio times the successful requests once
+// the initial MaxTokens are spent. A RetryBudget is safe for concurrent use.
+type RetryBudget struct {
+	mu        sync.Mutex
+	tokens    float64
+	maxTokens float64
+	ratio     float64
+}
+
+// NewRetryBudget creates a full budget of maxTokens retries that earns ratio
+// retries per successful request
+func NewRetryBudget(maxTokens int, ratio float64) *RetryBudget {
+	return &RetryBudget{
+		tokens:    float64(maxTokens),
+		maxTokens: float64(maxTokens),
+		ratio:     ratio,
+	}
+}
+
+// Withdraw takes a token for a retry and reports whether one was available
+func (b *RetryBudget) Withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Deposit records a successful request
+func (b *RetryBudget) Deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.tokens+b.ratio, b.maxTokens)
+}
+
+// Tokens returns the number of retries currently available
+func (b *RetryBudget) Tokens() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.tokens
+}
+
+// Attempt describes an attempt of a request sent by DoWithRetry
+type Attempt struct {
+	// Number is the attempt number, starting at 1
+	Number int
+	// RetryReason is the errors.Kind of the error that caused this retry,
+	// or "" for the first attempt
+	RetryReason string
+}
+
+type attemptKey struct{}
+
+// AttemptFromContext returns the attempt described by a context passed by
+// DoWithRetry to its send function
+func AttemptFromContext(ctx context.Context) (Attempt, bool) {
+	attempt, ok := ctx.Value(attemptKey{}).(Attempt)
+	return attempt, ok
+}
+
+type maxRetriesKey struct{}
+
+type retryPolicyKey struct{}
+
+// WithRetries returns a context that overrides the maximum number of retries
+// of requests made with it. Zero, or a negative value, disables retries for
+// the call.
+func WithRetries(ctx context.Context, maxRetries int) context.Context {
+	return context.WithValue(ctx, maxRetriesKey{}, maxRetries)
+}
+
+// WithRetryPolicy returns a context that overrides the retry policy of
+// requests made with it
+func WithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
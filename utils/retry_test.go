@@ -0,0 +1,258 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/errors"
+)
+
+func TestRetryConfig_Backoff(t *testing.T) {
+	config := RetryConfig{
+		BaseDelay:  100 * time.Millisecond,
+		MaxDelay:   time.Second,
+		Multiplier: 2.0,
+		Jitter:     JitterNone,
+	}
+
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+	for i, want := range expected {
+		if got := config.Backoff(i+1, 0); got != want {
+			t.Errorf("Backoff(%d) = %v, expected %v", i+1, got, want)
+		}
+	}
+
+	config.Jitter = JitterFull
+	for i := 0; i < 100; i++ {
+		if got := config.Backoff(3, 0); got < 0 || got > 400*time.Millisecond {
+			t.Fatalf("Full jitter delay %v out of range", got)
+		}
+	}
+
+	config.Jitter = JitterDecorrelated
+	for i := 0; i < 100; i++ {
+		got := config.Backoff(2, 200*time.Millisecond)
+		if got < 100*time.Millisecond || got > 600*time.Millisecond {
+			t.Fatalf("Decorrelated jitter delay %v out of range", got)
+		}
+	}
+}
+
+func TestWithRetry_RetryAfter(t *testing.T) {
+	config := RetryConfig{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	calls := 0
+	start := time.Now()
+	err := WithRetry(context.Background(), config, func() error {
+		calls++
+		if calls == 1 {
+			return errors.NewRateLimitError(1)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithRetry() returned error: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected to wait the Retry-After delay, waited %v", elapsed)
+	}
+}
+
+func TestWithRetry_RetryAfterExceedsDeadline(t *testing.T) {
+	config := RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	start := time.Now()
+	err := WithRetry(ctx, config, func() error {
+		calls++
+		return errors.NewRateLimitError(30)
+	})
+
+	if _, ok := err.(*errors.RateLimitError); !ok {
+		t.Fatalf("Expected RateLimitError, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single attempt, got %d", calls)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Expected to give up without waiting, waited %v", elapsed)
+	}
+}
+
+func TestWithRetry_RetryAfterExceedsMax(t *testing.T) {
+	config := RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond, MaxRetryAfter: time.Second}
+
+	calls := 0
+	start := time.Now()
+	err := WithRetry(context.Background(), config, func() error {
+		calls++
+		return errors.NewRateLimitError(3600)
+	})
+
+	if _, ok := err.(*errors.RateLimitError); !ok {
+		t.Fatalf("Expected RateLimitError, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single attempt, got %d", calls)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Expected to give up without waiting, waited %v", elapsed)
+	}
+}
+
+func TestDefaultRetryPolicy_ShouldRetry(t *testing.T) {
+	written := errors.NewNetworkError(context.DeadlineExceeded)
+	written.RequestWritten = true
+	notWritten := errors.NewNetworkError(context.DeadlineExceeded)
+
+	tests := []struct {
+		method   string
+		err      error
+		expected RetryDecision
+	}{
+		{http.MethodGet, written, RetrySafe},
+		{http.MethodPost, written, RetryIfAbsent},
+		{http.MethodPost, notWritten, RetrySafe},
+		{http.MethodPost, errors.NewRateLimitError(0), RetrySafe},
+		{http.MethodPost, errors.NewDokanError("too_many", "slow down", 429), RetrySafe},
+		{http.MethodPut, errors.NewDokanError("internal_error", "oops", 500), RetrySafe},
+		{http.MethodPost, errors.NewDokanError("internal_error", "oops", 500), RetryIfAbsent},
+		{http.MethodGet, errors.NewDokanError("bad_request", "bad", 400), RetryAbort},
+		{http.MethodPost, errors.NewDokanError("product_invalid_sku", "duplicate", 400), RetryAbort},
+		{http.MethodGet, errors.NewAuthenticationError("unauthorized access"), RetryAbort},
+		{http.MethodGet, errors.NewValidationError("name", "required", "name is required"), RetryAbort},
+		{http.MethodGet, errors.NewNotFoundError("product", 1), RetryAbort},
+		{http.MethodGet, errors.NewCircuitOpenError(time.Now()), RetryAbort},
+	}
+
+	for _, tt := range tests {
+		if got := (DefaultRetryPolicy{}).ShouldRetry(tt.method, tt.err); got != tt.expected {
+			t.Errorf("ShouldRetry(%s, %v) = %v, expected %v", tt.method, tt.err, got, tt.expected)
+		}
+	}
+}
+
+func TestDoWithRetry_NonIdempotent(t *testing.T) {
+	config := RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	lost := errors.NewNetworkError(context.DeadlineExceeded)
+	lost.RequestWritten = true
+
+	calls := 0
+	_, err := DoWithRetry(context.Background(), config, RequestOptions{Method: http.MethodPost}, func(context.Context) (*Response, error) {
+		calls++
+		return nil, lost
+	})
+	if err != lost {
+		t.Fatalf("Expected the network error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected POST without Dedupe not to be retried, got %d attempts", calls)
+	}
+}
+
+func TestDoWithRetry_Dedupe(t *testing.T) {
+	config := RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	lost := errors.NewNetworkError(context.DeadlineExceeded)
+	lost.RequestWritten = true
+	existing := &Response{StatusCode: http.StatusCreated, Body: []byte(`{"id": 7}`)}
+
+	lookups := 0
+	opts := RequestOptions{
+		Method: http.MethodPost,
+		Dedupe: func(ctx context.Context) (*Response, error) {
+			lookups++
+			if lookups == 1 {
+				return nil, nil
+			}
+			return existing, nil
+		},
+	}
+
+	calls := 0
+	resp, err := DoWithRetry(context.Background(), config, opts, func(context.Context) (*Response, error) {
+		calls++
+		return nil, lost
+	})
+	if err != nil {
+		t.Fatalf("DoWithRetry() returned error: %v", err)
+	}
+	if resp != existing {
+		t.Errorf("Expected the deduplicated response, got %+v", resp)
+	}
+	if calls != 2 || lookups != 2 {
+		t.Errorf("Expected 2 attempts and 2 lookups, got %d and %d", calls, lookups)
+	}
+}
+
+func TestDoWithRetry_ContextOverrides(t *testing.T) {
+	config := RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	failing := errors.NewDokanError("internal_error", "oops", 500)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected int
+	}{
+		{"default", context.Background(), 4},
+		{"WithRetries", WithRetries(context.Background(), 1), 2},
+		{"WithRetries zero", WithRetries(context.Background(), 0), 1},
+		{"WithRetries negative", WithRetries(context.Background(), -1), 1},
+		{"WithRetryPolicy", WithRetryPolicy(context.Background(), NoRetryPolicy{}), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			_, err := DoWithRetry(tt.ctx, config, RequestOptions{Method: http.MethodGet}, func(context.Context) (*Response, error) {
+				calls++
+				return nil, failing
+			})
+			if calls != tt.expected {
+				t.Errorf("Expected %d attempts, got %d", tt.expected, calls)
+			}
+			if err != failing {
+				t.Errorf("Expected the last error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	budget := NewRetryBudget(2, 0.5)
+	config := RetryConfig{MaxRetries: 5, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Budget: budget}
+	failing := errors.NewDokanError("internal_error", "oops", 500)
+
+	calls := 0
+	DoWithRetry(context.Background(), config, RequestOptions{Method: http.MethodGet}, func(context.Context) (*Response, error) {
+		calls++
+		return nil, failing
+	})
+	if calls != 3 {
+		t.Errorf("Expected the budget to allow 2 retries, got %d attempts", calls)
+	}
+	if budget.Withdraw() {
+		t.Error("Expected the budget to be exhausted")
+	}
+
+	for i := 0; i < 2; i++ {
+		DoWithRetry(context.Background(), config, RequestOptions{Method: http.MethodGet}, func(context.Context) (*Response, error) {
+			return &Response{StatusCode: http.StatusOK}, nil
+		})
+	}
+	if tokens := budget.Tokens(); tokens != 1 {
+		t.Errorf("Expected successes to deposit 1 token, got %v", tokens)
+	}
+}
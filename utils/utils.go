@@ -6,11 +6,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"math/rand/v2"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/diogenes-moreira/dokan-go-sdk/errors"
@@ -21,6 +25,34 @@ type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// HTTPClientFunc adapts a function to the HTTPClient interface
+type HTTPClientFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req)
+func (f HTTPClientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps an HTTPClient to observe or modify the requests it sends
+// and the responses it returns
+type Middleware func(next HTTPClient) HTTPClient
+
+// Chain wraps client with middleware. The first middleware is the outermost
+// one: it sees the request first and the response last.
+func Chain(client HTTPClient, middleware ...Middleware) HTTPClient {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		client = middleware[i](client)
+	}
+	return client
+}
+
+// RequestFunc makes a request, including any retries
+type RequestFunc func(ctx context.Context, opts RequestOptions) (*Response, error)
+
+// RequestMiddleware wraps a RequestFunc to observe or modify whole requests,
+// as opposed to Middleware, which sees every attempt separately
+type RequestMiddleware func(next RequestFunc) RequestFunc
+
 // RequestOptions contains options for making HTTP requests
 type RequestOptions struct {
 	Method  string
@@ -28,6 +60,19 @@ type RequestOptions struct {
 	Query   interface{}
 	Body    interface{}
 	Headers map[string]string
+
+	// Operation names the API operation, such as "products.update", in
+	// logs, traces and metrics
+	Operation string
+	// ResourceIDs holds the IDs of the resources the request acts on, keyed
+	// by name such as "product_id"
+	ResourceIDs map[string]int
+
+	// Dedupe is called before retrying a non-idempotent request whose
+	// previous attempt may have been committed by the server. It returns the
+	// resource created by that attempt, or nil if there is none, in which
+	// case the request is retried.
+	Dedupe func(ctx context.Context) (*Response, error)
 }
 
 // Response represents an HTTP response
@@ -66,6 +111,18 @@ func MakeRequest(ctx context.Context, client HTTPClient, baseURL string, opts Re
 		body = bytes.NewReader(jsonBody)
 	}
 	
+	// Track whether the request reached the wire so that retries can tell
+	// a connection failure from a response lost after the server got it
+	var traced, written atomic.Bool
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GetConn: func(string) { traced.Store(true) },
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			if info.Err == nil {
+				written.Store(true)
+			}
+		},
+	})
+	
 	// Create request
 	req, err := http.NewRequestWithContext(ctx, opts.Method, u.String(), body)
 	if err != nil {
@@ -85,7 +142,10 @@ func MakeRequest(ctx context.Context, client HTTPClient, baseURL string, opts Re
 	// Make request
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, errors.NewNetworkError(err)
+		networkErr := errors.NewNetworkError(err)
+		// Clients that do not report trace events may have sent the request
+		networkErr.RequestWritten = written.Load() || !traced.Load()
+		return nil, networkErr
 	}
 	defer resp.Body.Close()
 	
@@ -107,17 +167,29 @@ func MakeRequest(ctx context.Context, client HTTPClient, baseURL string, opts Re
 		var dokanErr errors.DokanError
 		if err := json.Unmarshal(respBody, &dokanErr); err == nil && dokanErr.Code != "" {
 			dokanErr.StatusCode = resp.StatusCode
+			dokanErr.RetryAfter = errors.RetryAfterSeconds(resp.Header, time.Now())
 			return response, &dokanErr
 		}
 		
 		// Fall back to generic HTTP error
-		return response, errors.HandleHTTPError(resp.StatusCode, respBody)
+		return response, errors.HandleHTTPResponse(resp.StatusCode, resp.Header, respBody)
 	}
 	
 	return response, nil
 }
 
-// StructToURLValues converts a struct to url.Values using struct tags
+// StructToURLValues converts a struct to url.Values using `url` struct tags.
+//
+// Anonymous embedded structs without a tag are flattened into the parent, so
+// the fields of an embedded types.ListParams are encoded alongside the fields
+// of the struct that embeds it. Tagged struct fields are encoded with their
+// name as a prefix, e.g. `filter[name]=value`.
+//
+// Slices are comma-joined by default. The `brackets` tag option encodes them
+// as repeated `key[]=value` pairs and the `repeat` option as repeated
+// `key=value` pairs:
+//
+//	Status []OrderStatus `url:"status,omitempty,brackets"`
 func StructToURLValues(v interface{}) (url.Values, error) {
 	values := url.Values{}
 	
@@ -127,6 +199,9 @@ func StructToURLValues(v interface{}) (url.Values, error) {
 	
 	rv := reflect.ValueOf(v)
 	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return values, nil
+		}
 		rv = rv.Elem()
 	}
 	
@@ -134,49 +209,145 @@ func StructToURLValues(v interface{}) (url.Values, error) {
 		return nil, fmt.Errorf("expected struct, got %T", v)
 	}
 	
+	if err := encodeStruct(values, rv, ""); err != nil {
+		return nil, err
+	}
+	
+	return values, nil
+}
+
+// sliceStyle controls how slice values are encoded in a query string
+type sliceStyle int
+
+const (
+	sliceStyleComma sliceStyle = iota
+	sliceStyleBrackets
+	sliceStyleRepeat
+)
+
+// urlTag holds the parsed options of a `url` struct tag
+type urlTag struct {
+	name      string
+	omitEmpty bool
+	style     sliceStyle
+}
+
+// parseURLTag parses a `url` struct tag
+func parseURLTag(tag string) urlTag {
+	tagParts := strings.Split(tag, ",")
+	parsed := urlTag{name: tagParts[0]}
+	for _, option := range tagParts[1:] {
+		switch option {
+		case "omitempty":
+			parsed.omitEmpty = true
+		case "brackets":
+			parsed.style = sliceStyleBrackets
+		case "repeat":
+			parsed.style = sliceStyleRepeat
+		case "comma":
+			parsed.style = sliceStyleComma
+		}
+	}
+	return parsed
+}
+
+// encodeStruct adds the tagged fields of a struct to values, prefixing each
+// key with prefix when encoding a nested struct
+func encodeStruct(values url.Values, rv reflect.Value, prefix string) error {
 	rt := rv.Type()
 	for i := 0; i < rv.NumField(); i++ {
 		field := rv.Field(i)
 		fieldType := rt.Field(i)
 		
-		// Skip unexported fields
-		if !field.CanInterface() {
+		tag := fieldType.Tag.Get("url")
+		if tag == "-" {
 			continue
 		}
 		
-		// Get the tag
-		tag := fieldType.Tag.Get("url")
-		if tag == "" || tag == "-" {
+		// Flatten anonymous embedded structs such as types.ListParams
+		if fieldType.Anonymous && tag == "" {
+			embedded := field
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := encodeStruct(values, embedded, prefix); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		
+		// Skip unexported and untagged fields
+		if !fieldType.IsExported() || tag == "" {
 			continue
 		}
 		
-		// Parse tag options
-		tagParts := strings.Split(tag, ",")
-		name := tagParts[0]
-		omitEmpty := false
-		for _, option := range tagParts[1:] {
-			if option == "omitempty" {
-				omitEmpty = true
-			}
+		options := parseURLTag(tag)
+		name := options.name
+		if prefix != "" {
+			name = prefix + "[" + name + "]"
 		}
 		
 		// Skip empty values if omitempty is set
-		if omitEmpty && isEmptyValue(field) {
+		if options.omitEmpty && isEmptyValue(field) {
 			continue
 		}
 		
-		// Convert field value to string
-		value, err := fieldToString(field)
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert field %s: %w", fieldType.Name, err)
+		if err := encodeValue(values, name, field, options); err != nil {
+			return fmt.Errorf("failed to convert field %s: %w", fieldType.Name, err)
 		}
-		
-		if value != "" {
-			values.Add(name, value)
+	}
+	
+	return nil
+}
+
+// encodeValue adds a single field value to values under name
+func encodeValue(values url.Values, name string, v reflect.Value, options urlTag) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
 		}
+		v = v.Elem()
 	}
 	
-	return values, nil
+	switch v.Kind() {
+	case reflect.Struct:
+		// time.Time is encoded as a single value rather than as a nested struct
+		if _, ok := v.Interface().(time.Time); !ok {
+			return encodeStruct(values, v, name)
+		}
+	case reflect.Slice, reflect.Array:
+		if options.style == sliceStyleComma {
+			break
+		}
+		key := name
+		if options.style == sliceStyleBrackets {
+			key = name + "[]"
+		}
+		for i := 0; i < v.Len(); i++ {
+			value, err := fieldToString(v.Index(i))
+			if err != nil {
+				return err
+			}
+			values.Add(key, value)
+		}
+		return nil
+	}
+	
+	value, err := fieldToString(v)
+	if err != nil {
+		return err
+	}
+	
+	if value != "" {
+		values.Add(name, value)
+	}
+	
+	return nil
 }
 
 // isEmptyValue checks if a reflect.Value is empty
@@ -213,22 +384,17 @@ func fieldToString(v reflect.Value) (string, error) {
 		return strconv.FormatFloat(v.Float(), 'f', -1, 32), nil
 	case reflect.Float64:
 		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
-	case reflect.Slice:
-		if v.Type().Elem().Kind() == reflect.String {
-			var strs []string
-			for i := 0; i < v.Len(); i++ {
-				strs = append(strs, v.Index(i).String())
+	case reflect.Slice, reflect.Array:
+		// Elements may be named types such as []OrderStatus or []ProductType
+		var strs []string
+		for i := 0; i < v.Len(); i++ {
+			str, err := fieldToString(v.Index(i))
+			if err != nil {
+				return "", fmt.Errorf("unsupported slice type: %s", v.Type())
 			}
-			return strings.Join(strs, ","), nil
+			strs = append(strs, str)
 		}
-		if v.Type().Elem().Kind() == reflect.Int {
-			var strs []string
-			for i := 0; i < v.Len(); i++ {
-				strs = append(strs, strconv.FormatInt(v.Index(i).Int(), 10))
-			}
-			return strings.Join(strs, ","), nil
-		}
-		return "", fmt.Errorf("unsupported slice type: %s", v.Type())
+		return strings.Join(strs, ","), nil
 	case reflect.Ptr:
 		if v.IsNil() {
 			return "", nil
@@ -269,59 +435,215 @@ func BuildPath(path string, params ...interface{}) string {
 	return path
 }
 
+// Jitter selects how random jitter is applied to retry delays
+type Jitter int
+
+const (
+	// JitterFull waits a random delay between zero and the exponential
+	// backoff delay
+	JitterFull Jitter = iota
+	// JitterDecorrelated waits a random delay between BaseDelay and three
+	// times the previous delay
+	JitterDecorrelated
+	// JitterNone waits exactly the exponential backoff delay
+	JitterNone
+)
+
+// DefaultMaxRetryAfter is the longest Retry-After delay waited for when
+// RetryConfig.MaxRetryAfter is zero
+const DefaultMaxRetryAfter = 2 * time.Minute
+
 // RetryConfig represents retry configuration
 type RetryConfig struct {
 	MaxRetries int
 	BaseDelay  time.Duration
 	MaxDelay   time.Duration
 	Multiplier float64
+	Jitter     Jitter
+	// MaxRetryAfter is the longest Retry-After delay waited for. Requests
+	// asked to wait longer fail with the last error instead. Zero uses
+	// DefaultMaxRetryAfter.
+	MaxRetryAfter time.Duration
+	// Policy decides which failures are retried and how long to wait
+	// between attempts. Nil classifies errors like DefaultRetryPolicy and
+	// waits the backoff delays of this configuration.
+	Policy RetryPolicy
+	// Budget, if set, limits the retries of every request that shares it
+	Budget *RetryBudget
 }
 
 // DefaultRetryConfig returns a default retry configuration
 func DefaultRetryConfig() RetryConfig {
 	return RetryConfig{
-		MaxRetries: 3,
-		BaseDelay:  1 * time.Second,
-		MaxDelay:   30 * time.Second,
-		Multiplier: 2.0,
+		MaxRetries:    3,
+		BaseDelay:     1 * time.Second,
+		MaxDelay:      30 * time.Second,
+		Multiplier:    2.0,
+		Jitter:        JitterFull,
+		MaxRetryAfter: DefaultMaxRetryAfter,
+	}
+}
+
+// Backoff returns the delay before retry number attempt (starting at 1).
+// prev is the previous delay and is only used by JitterDecorrelated.
+func (c RetryConfig) Backoff(attempt int, prev time.Duration) time.Duration {
+	if c.BaseDelay <= 0 {
+		return 0
+	}
+
+	multiplier := c.Multiplier
+	if multiplier < 1 {
+		multiplier = 2.0
 	}
+	maxDelay := c.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = time.Duration(math.MaxInt64)
+	}
+
+	if c.Jitter == JitterDecorrelated {
+		upper := prev * 3
+		if upper < c.BaseDelay {
+			upper = c.BaseDelay
+		}
+		delay := c.BaseDelay + randomDuration(upper-c.BaseDelay)
+		return min(delay, maxDelay)
+	}
+
+	exp := float64(c.BaseDelay) * math.Pow(multiplier, float64(attempt-1))
+	delay := maxDelay
+	if exp < float64(maxDelay) {
+		delay = time.Duration(exp)
+	}
+
+	if c.Jitter == JitterFull {
+		return randomDuration(delay)
+	}
+	return delay
 }
 
-// WithRetry executes a function with retry logic
+// randomDuration returns a random duration in [0, d]
+func randomDuration(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	if d == math.MaxInt64 {
+		return time.Duration(rand.Int64N(int64(d)))
+	}
+	return time.Duration(rand.Int64N(int64(d) + 1))
+}
+
+// WithRetry executes a function with retry logic, treating it as an
+// idempotent request. See DoWithRetry.
 func WithRetry(ctx context.Context, config RetryConfig, fn func() error) error {
-	var lastErr error
+	_, err := DoWithRetry(ctx, config, RequestOptions{Method: http.MethodGet}, func(context.Context) (*Response, error) {
+		return nil, fn()
+	})
+	return err
+}
+
+// DoWithRetry sends the request described by opts with send, retrying the
+// failures that the retry policy allows for opts.Method. Between attempts it
+// waits the delay requested by the server through Retry-After when there is
+// one, and the backoff delay of the policy otherwise. If the wait would
+// outlast the context deadline, or the Retry-After delay exceeds
+// config.MaxRetryAfter, the last error is returned immediately.
+//
+// The policy and the maximum number of retries of config can be overridden
+// for a single call through the context; see WithRetries and WithRetryPolicy.
+// A retry is skipped when config.Budget is exhausted.
+//
+// When the policy answers RetryIfAbsent, opts.Dedupe is called after the wait
+// and the resource it finds, if any, is returned instead of sending the
+// request again.
+//
+// send is called with a context derived from ctx that describes the attempt;
+// see AttemptFromContext.
+func DoWithRetry(ctx context.Context, config RetryConfig, opts RequestOptions, send func(ctx context.Context) (*Response, error)) (*Response, error) {
+	policy := config.Policy
+	if override, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy); ok {
+		policy = override
+	}
+	if policy == nil {
+		policy = configPolicy{config: config}
+	}
+	
+	maxRetries := config.MaxRetries
+	if override, ok := ctx.Value(maxRetriesKey{}).(int); ok {
+		maxRetries = override
+	}
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	
+	maxRetryAfter := config.MaxRetryAfter
+	if maxRetryAfter <= 0 {
+		maxRetryAfter = DefaultMaxRetryAfter
+	}
+	
+	var (
+		lastResp *Response
+		lastErr  error
+		decision RetryDecision
+		delay    time.Duration
+	)
 	
-	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
-			// Calculate delay with exponential backoff
-			delay := time.Duration(float64(config.BaseDelay) * float64(attempt) * config.Multiplier)
-			if delay > config.MaxDelay {
-				delay = config.MaxDelay
+			if retryAfter, ok := errors.RetryAfter(lastErr); ok {
+				if retryAfter > maxRetryAfter {
+					return lastResp, lastErr
+				}
+				delay = retryAfter
+			} else {
+				delay = policy.Backoff(attempt, delay)
+			}
+			
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+				return lastResp, lastErr
+			}
+			
+			if config.Budget != nil && !config.Budget.Withdraw() {
+				return lastResp, lastErr
 			}
 			
 			// Wait with context cancellation support
+			timer := time.NewTimer(delay)
 			select {
 			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(delay):
+				timer.Stop()
+				return lastResp, ctx.Err()
+			case <-timer.C:
+			}
+			
+			// Look for the resource the previous attempt may have created
+			if decision == RetryIfAbsent {
+				existing, err := opts.Dedupe(ctx)
+				if err != nil {
+					return lastResp, lastErr
+				}
+				if existing != nil {
+					return existing, nil
+				}
 			}
 		}
 		
-		lastErr = fn()
+		info := Attempt{Number: attempt + 1}
+		if attempt > 0 {
+			info.RetryReason = errors.Kind(lastErr)
+		}
+		lastResp, lastErr = send(context.WithValue(ctx, attemptKey{}, info))
 		if lastErr == nil {
-			return nil
+			if config.Budget != nil {
+				config.Budget.Deposit()
+			}
+			return lastResp, nil
 		}
 		
-		// Don't retry certain types of errors
-		if errors.IsDokanError(lastErr) {
-			dokanErr := lastErr.(*errors.DokanError)
-			// Don't retry client errors (4xx) except rate limiting
-			if dokanErr.StatusCode >= 400 && dokanErr.StatusCode < 500 && dokanErr.StatusCode != 429 {
-				return lastErr
-			}
+		decision = policy.ShouldRetry(opts.Method, lastErr)
+		if decision == RetryAbort || (decision == RetryIfAbsent && opts.Dedupe == nil) {
+			return lastResp, lastErr
 		}
 	}
 	
-	return lastErr
+	return lastResp, lastErr
 }
-
@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/diogenes-moreira/dokan-go-sdk/types"
+)
+
+func TestStructToURLValues_EmbeddedListParams(t *testing.T) {
+	featured := true
+	params := &types.ProductListParams{
+		ListParams: types.ListParams{
+			Page:    2,
+			PerPage: 50,
+			Search:  "shirt",
+			OrderBy: "date",
+			Order:   "desc",
+		},
+		Featured: &featured,
+		SKU:      "ABC-1",
+	}
+
+	values, err := StructToURLValues(params)
+	if err != nil {
+		t.Fatalf("StructToURLValues() returned error: %v", err)
+	}
+
+	expected := map[string]string{
+		"page":     "2",
+		"per_page": "50",
+		"search":   "shirt",
+		"orderby":  "date",
+		"order":    "desc",
+		"featured": "true",
+		"sku":      "ABC-1",
+	}
+	for key, want := range expected {
+		if got := values.Get(key); got != want {
+			t.Errorf("Expected %s=%q, got %q", key, want, got)
+		}
+	}
+}
+
+func TestStructToURLValues_NamedStringSlices(t *testing.T) {
+	params := &types.OrderListParams{
+		Status: []types.OrderStatus{types.OrderStatusProcessing, types.OrderStatusOnHold},
+	}
+
+	values, err := StructToURLValues(params)
+	if err != nil {
+		t.Fatalf("StructToURLValues() returned error: %v", err)
+	}
+
+	if got := values.Get("status"); got != "processing,on-hold" {
+		t.Errorf("Expected status=processing,on-hold, got %q", got)
+	}
+}
+
+func TestStructToURLValues_SliceStyles(t *testing.T) {
+	type params struct {
+		Comma    []types.ProductType `url:"type,omitempty"`
+		Brackets []string            `url:"status,omitempty,brackets"`
+		Repeat   []int               `url:"include,omitempty,repeat"`
+	}
+
+	values, err := StructToURLValues(params{
+		Comma:    []types.ProductType{types.ProductTypeSimple, types.ProductTypeVariable},
+		Brackets: []string{"a", "b"},
+		Repeat:   []int{1, 2},
+	})
+	if err != nil {
+		t.Fatalf("StructToURLValues() returned error: %v", err)
+	}
+
+	expected := url.Values{
+		"type":     {"simple,variable"},
+		"status[]": {"a", "b"},
+		"include":  {"1", "2"},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+}
+
+func TestStructToURLValues_NestedStruct(t *testing.T) {
+	type filter struct {
+		Name  string `url:"name,omitempty"`
+		Value string `url:"value,omitempty"`
+	}
+	type params struct {
+		Filter *filter `url:"filter,omitempty"`
+	}
+
+	values, err := StructToURLValues(params{Filter: &filter{Name: "color", Value: "red"}})
+	if err != nil {
+		t.Fatalf("StructToURLValues() returned error: %v", err)
+	}
+
+	if got := values.Get("filter[name]"); got != "color" {
+		t.Errorf("Expected filter[name]=color, got %q", got)
+	}
+	if got := values.Get("filter[value]"); got != "red" {
+		t.Errorf("Expected filter[value]=red, got %q", got)
+	}
+}
+
+func TestStructToURLValues_Time(t *testing.T) {
+	after := time.Date(2024, 3, 22, 16, 28, 2, 0, time.UTC)
+	values, err := StructToURLValues(&types.OrderListParams{After: &after})
+	if err != nil {
+		t.Fatalf("StructToURLValues() returned error: %v", err)
+	}
+
+	if got := values.Get("after"); got != "2024-03-22T16:28:02Z" {
+		t.Errorf("Expected after=2024-03-22T16:28:02Z, got %q", got)
+	}
+}
+
+func TestStructToURLValues_NilPointer(t *testing.T) {
+	var params *types.ProductListParams
+
+	values, err := StructToURLValues(params)
+	if err != nil {
+		t.Fatalf("StructToURLValues() returned error: %v", err)
+	}
+
+	if len(values) != 0 {
+		t.Errorf("Expected no values, got %v", values)
+	}
+}
+
+func TestChain(t *testing.T) {
+	var order []string
+	record := func(name string) Middleware {
+		return func(next HTTPClient) HTTPClient {
+			return HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name+" before")
+				resp, err := next.Do(req)
+				order = append(order, name+" after")
+				return resp, err
+			})
+		}
+	}
+	client := HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+		order = append(order, "client")
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	if _, err := Chain(client, record("first"), record("second")).Do(req); err != nil {
+		t.Fatalf("Do() returned error: %v", err)
+	}
+
+	expected := []string{"first before", "second before", "client", "second after", "first after"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("Expected order %v, got %v", expected, order)
+	}
+}